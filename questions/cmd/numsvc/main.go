@@ -0,0 +1,54 @@
+// Command numsvc serves the Fibonacci and Luhn batch endpoints of package numsvc.
+//
+//	numsvc [-addr :8080] [-max-body bytes] [-max-items n] [-max-digits n]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"greystonetec.co.za/tests/go-candidate-test/numsvc"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "listen address")
+	maxBody := flag.Int64("max-body", numsvc.DefaultMaxBodyBytes, "maximum request body size in bytes")
+	maxItems := flag.Int("max-items", numsvc.DefaultMaxItems, "maximum items per batch")
+	maxDigits := flag.Int("max-digits", numsvc.DefaultMaxDigits, "maximum digits per number")
+	flag.Parse()
+
+	srv := &http.Server{
+		Addr: *addr,
+		Handler: numsvc.NewServer(
+			numsvc.WithMaxBodyBytes(*maxBody),
+			numsvc.WithMaxItems(*maxItems),
+			numsvc.WithMaxDigits(*maxDigits),
+		),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("numsvc: shutdown: %v", err)
+		}
+	}()
+
+	log.Printf("numsvc: listening on %s", *addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("numsvc: %v", err)
+	}
+}
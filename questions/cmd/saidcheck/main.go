@@ -0,0 +1,130 @@
+// Command saidcheck parses and validates South African ID numbers.
+//
+//	saidcheck [-json] [-at YYYY-MM-DD] [id ...]
+//
+// IDs are taken from the arguments, or one per line from stdin when there are none.
+// The exit status is 0 when every ID is valid, 1 when any is invalid and 2 on usage or read errors.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"greystonetec.co.za/tests/go-candidate-test/said"
+)
+
+const (
+	exitValid   = 0
+	exitInvalid = 1
+	exitUsage   = 2
+)
+
+// result is a single line of -json output
+type result struct {
+	ID          string      `json:"id"`
+	Valid       bool        `json:"valid"`
+	Error       string      `json:"error,omitempty"`
+	Code        string      `json:"code,omitempty"`
+	Gender      said.Gender `json:"gender,omitempty"`
+	DateOfBirth string      `json:"dateOfBirth,omitempty"`
+	Age         *int        `json:"age,omitempty"`
+	Citizenship string      `json:"citizenship,omitempty"`
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("saidcheck", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	asJSON := fs.Bool("json", false, "print one JSON object per ID")
+	at := fs.String("at", "", "calculate ages as at this date (YYYY-MM-DD), defaults to today")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	var opts []said.SAIDParserOption
+	if *at != "" {
+		now, err := time.Parse("2006-01-02", *at)
+		if err != nil {
+			fmt.Fprintf(stderr, "saidcheck: invalid -at date: %v\n", err)
+			return exitUsage
+		}
+		opts = append(opts, said.WithClock(func() time.Time { return now }))
+	}
+	parser := said.NewSAIDParser(opts...)
+
+	enc := json.NewEncoder(stdout)
+	status := exitValid
+	check := func(id string) {
+		details, err := parser.Parse(id)
+		if err != nil {
+			status = exitInvalid
+		}
+		if *asJSON {
+			_ = enc.Encode(newResult(id, details, err))
+		} else {
+			fmt.Fprintln(stdout, describe(id, details, err))
+		}
+	}
+
+	if fs.NArg() > 0 {
+		for _, id := range fs.Args() {
+			check(id)
+		}
+		return status
+	}
+
+	sc := bufio.NewScanner(stdin)
+	for sc.Scan() {
+		if id := strings.TrimSpace(sc.Text()); id != "" {
+			check(id)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		fmt.Fprintf(stderr, "saidcheck: reading stdin: %v\n", err)
+		return exitUsage
+	}
+	return status
+}
+
+func newResult(id string, details *said.SAIDDetails, err error) result {
+	if err != nil {
+		return result{ID: id, Error: err.Error(), Code: said.ErrorCode(err)}
+	}
+	return result{
+		ID:          id,
+		Valid:       true,
+		Gender:      details.Gender,
+		DateOfBirth: details.DateOfBirth.Format("2006-01-02"),
+		Age:         &details.Age,
+		Citizenship: details.Citizenship.String(),
+	}
+}
+
+func describe(id string, details *said.SAIDDetails, err error) string {
+	if err != nil {
+		return fmt.Sprintf("%s: invalid: %v", id, err)
+	}
+
+	citizenship := "SA citizen"
+	switch details.Citizenship {
+	case said.PermanentResident:
+		citizenship = "permanent resident"
+	case said.Refugee:
+		citizenship = "refugee"
+	}
+	gender := "female"
+	if details.Gender == said.Male {
+		gender = "male"
+	}
+	return fmt.Sprintf("%s: valid: %s, born %s (age %d), %s",
+		id, gender, details.DateOfBirth.Format("2006-01-02"), details.Age, citizenship)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunArgs(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-at", "2021-06-01", "9103105017084", "9101015017089"}, nil, &stdout, &stderr)
+
+	assert.Equal(t, exitInvalid, code)
+	assert.Equal(t, "9103105017084: valid: male, born 1991-03-10 (age 30), SA citizen\n"+
+		"9101015017089: invalid: luhn checksum failed at position 12\n", stdout.String())
+	assert.Empty(t, stderr.String())
+}
+
+func TestRunStdinJSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	stdin := strings.NewReader("9103105017084\n\n 9604185215084\n")
+	code := run([]string{"-json", "-at", "2021-06-01"}, stdin, &stdout, &stderr)
+
+	assert.Equal(t, exitValid, code)
+	assert.Equal(t,
+		`{"id":"9103105017084","valid":true,"gender":"M","dateOfBirth":"1991-03-10","age":30,"citizenship":"citizen"}`+"\n"+
+			`{"id":"9604185215084","valid":true,"gender":"M","dateOfBirth":"1996-04-18","age":25,"citizenship":"citizen"}`+"\n",
+		stdout.String())
+}
+
+func TestRunJSONInvalid(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-json", "910310501708"}, nil, &stdout, &stderr)
+
+	assert.Equal(t, exitInvalid, code)
+	assert.Equal(t, `{"id":"910310501708","valid":false,"error":"invalid length at position 12","code":"length"}`+"\n", stdout.String())
+}
+
+func TestRunUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitUsage, run([]string{"-at", "yesterday"}, nil, &stdout, &stderr))
+	assert.Equal(t, exitUsage, run([]string{"-nope"}, nil, &stdout, &stderr))
+}
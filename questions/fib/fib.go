@@ -0,0 +1,35 @@
+// Package fib has Fibonacci number helpers, the sequence is taken to start 0, 1, 1, 2, 3, 5, ...
+package fib
+
+import (
+	"math/big"
+	"slices"
+)
+
+// IsFibonacci reports whether n is a Fibonacci number, exactly for every int64
+func IsFibonacci(n int64) bool {
+	_, found := slices.BinarySearch(table(), n)
+	return found
+}
+
+// IsFibonacciBig reports whether n is a Fibonacci number, using the fact that n is one
+// exactly when 5n²+4 or 5n²-4 is a perfect square
+func IsFibonacciBig(n *big.Int) bool {
+	if n.Sign() < 0 {
+		return false
+	}
+
+	x := new(big.Int).Mul(n, n)
+	x.Mul(x, big.NewInt(5))
+
+	four := big.NewInt(4)
+	return isSquare(new(big.Int).Add(x, four)) || isSquare(x.Sub(x, four))
+}
+
+func isSquare(x *big.Int) bool {
+	if x.Sign() < 0 {
+		return false
+	}
+	r := new(big.Int).Sqrt(x)
+	return r.Mul(r, r).Cmp(x) == 0
+}
@@ -0,0 +1,66 @@
+package fib
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// F92 is the largest Fibonacci number that fits in an int64
+const f92 int64 = 7540113804746346429
+
+func TestIsFibonacci(t *testing.T) {
+	a, b := int64(0), int64(1)
+	for i := 0; i <= 92; i++ {
+		assert.True(t, IsFibonacci(a), "F%d = %d", i, a)
+		if a > 3 {
+			assert.False(t, IsFibonacci(a+1), "F%d+1 = %d", i, a+1)
+			assert.False(t, IsFibonacci(a-1), "F%d-1 = %d", i, a-1)
+		}
+		if i < 92 {
+			a, b = b, a+b
+		}
+	}
+	assert.Equal(t, f92, a)
+
+	assert.True(t, IsFibonacci(39088169))
+	assert.False(t, IsFibonacci(701408732))
+	assert.False(t, IsFibonacci(4))
+	assert.False(t, IsFibonacci(-1))
+	assert.False(t, IsFibonacci(math.MinInt64))
+	assert.False(t, IsFibonacci(f92+1))
+	assert.False(t, IsFibonacci(math.MaxInt64))
+}
+
+func TestIsFibonacciBig(t *testing.T) {
+	a, b := big.NewInt(0), big.NewInt(1)
+	one := big.NewInt(1)
+	for i := 0; i <= 500; i++ {
+		assert.True(t, IsFibonacciBig(a), "F%d = %s", i, a)
+		if i > 4 {
+			assert.False(t, IsFibonacciBig(new(big.Int).Add(a, one)), "F%d+1", i)
+			assert.False(t, IsFibonacciBig(new(big.Int).Sub(a, one)), "F%d-1", i)
+		}
+		a, b = b, a.Add(a, b)
+	}
+
+	// either side of the int64 limit
+	assert.True(t, IsFibonacciBig(big.NewInt(f92)))
+	f93, _ := new(big.Int).SetString("12200160415121876738", 10)
+	assert.True(t, IsFibonacciBig(f93))
+	assert.False(t, IsFibonacciBig(big.NewInt(math.MaxInt64)))
+	assert.False(t, IsFibonacciBig(new(big.Int).Add(big.NewInt(math.MaxInt64), one)))
+
+	assert.False(t, IsFibonacciBig(big.NewInt(-1)))
+	assert.False(t, IsFibonacciBig(big.NewInt(4)))
+}
+
+func TestIsFibonacciMatchesBig(t *testing.T) {
+	for n := int64(-10); n < 100000; n++ {
+		if IsFibonacci(n) != IsFibonacciBig(big.NewInt(n)) {
+			t.Fatalf("mismatch at %d", n)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package fib
+
+import (
+	"math/big"
+	"math/bits"
+	"sync"
+)
+
+// table holds F0 to F92, every Fibonacci number that fits in an int64, built on first use
+var table = sync.OnceValue(func() []int64 {
+	t := make([]int64, 0, 93)
+	for _, f := range Sequence() {
+		t = append(t, f)
+	}
+	return t
+})
+
+// Nth64 returns Fn from the precomputed table, ok is false when it doesn't fit in an int64 (n > 92)
+func Nth64(n int) (f int64, ok bool) {
+	t := table()
+	if n < 0 || n >= len(t) {
+		return 0, false
+	}
+	return t[n], true
+}
+
+// Nth returns Fn in O(log n) big.Int operations by fast doubling:
+//
+//	F(2k)   = F(k) * (2F(k+1) - F(k))
+//	F(2k+1) = F(k+1)² + F(k)²
+//
+// It panics if n is negative.
+func Nth(n int) *big.Int {
+	if n < 0 {
+		panic("fib: negative index")
+	}
+	if f, ok := Nth64(n); ok {
+		return big.NewInt(f)
+	}
+
+	a, b := big.NewInt(0), big.NewInt(1) // F(k), F(k+1) for k = the bits of n seen so far
+	c, d := new(big.Int), new(big.Int)
+	for i := bits.Len(uint(n)) - 1; i >= 0; i-- {
+		c.Lsh(b, 1).Sub(c, a).Mul(c, a) // F(2k)
+		d.Mul(a, a)
+		a.Mul(b, b).Add(a, d) // F(2k+1), a is free once c and d are done
+		if n>>uint(i)&1 == 0 {
+			a, b, c = c, a, b
+		} else {
+			c.Add(c, a) // F(2k+2)
+			b, c = c, b
+		}
+	}
+	return a
+}
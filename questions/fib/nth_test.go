@@ -0,0 +1,100 @@
+package fib
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNth(t *testing.T) {
+	for i, f := range SequenceBig() {
+		require.Zero(t, f.Cmp(Nth(i)), "F%d", i)
+		if i == 2000 {
+			break
+		}
+	}
+	assert.Equal(t, "12200160415121876738", Nth(93).String())
+	assert.Equal(t, "354224848179261915075", Nth(100).String())
+	assert.Panics(t, func() { Nth(-1) })
+}
+
+func TestNth64(t *testing.T) {
+	f, ok := Nth64(92)
+	assert.True(t, ok)
+	assert.Equal(t, f92, f)
+
+	_, ok = Nth64(93)
+	assert.False(t, ok)
+	_, ok = Nth64(-1)
+	assert.False(t, ok)
+}
+
+func TestTableConcurrentUse(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			f, _ := Nth64(n)
+			assert.True(t, IsFibonacci(f))
+		}(i)
+	}
+	wg.Wait()
+}
+
+// isFibNumberRecursive is the original recursive check from question2.go, for comparison
+func isFibNumberRecursive(valToCheck, previousValue, currentValue int) bool {
+	if valToCheck < 0 {
+		return false
+	}
+	if valToCheck == currentValue {
+		return true
+	}
+	if currentValue > valToCheck {
+		return false
+	}
+	return isFibNumberRecursive(valToCheck, currentValue, previousValue+currentValue)
+}
+
+func BenchmarkIsFibonacci(b *testing.B) {
+	const n = 4660046610375530309 // F91
+
+	b.Run("recursive", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			isFibNumberRecursive(n, 0, 1)
+		}
+	})
+	b.Run("table", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			IsFibonacci(n)
+		}
+	})
+	b.Run("big", func(b *testing.B) {
+		x := big.NewInt(n)
+		for i := 0; i < b.N; i++ {
+			IsFibonacciBig(x)
+		}
+	})
+}
+
+func BenchmarkNth(b *testing.B) {
+	const n = 10000
+
+	b.Run("iterative", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			a, c := big.NewInt(0), big.NewInt(1)
+			for j := 0; j < n; j++ {
+				a.Add(a, c)
+				a, c = c, a
+			}
+		}
+	})
+	b.Run("doubling", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Nth(n)
+		}
+	})
+}
@@ -0,0 +1,152 @@
+package fib
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"math/big"
+)
+
+var ErrInvalidRecurrence = errors.New("fib: invalid recurrence")
+
+// Recurrence is a linear recurrence a(n) = c[0]·a(n-1) + c[1]·a(n-2) + ... + c[k-1]·a(n-k)
+// started from the seeds a(0) to a(k-1).
+//
+// Coefficients and seeds must be non-negative and c[0] at least 1, so the terms never decrease
+// once past the seeds. That is what lets Contains and Index stop searching.
+type Recurrence struct {
+	coefficients []int64
+	seeds        []int64
+}
+
+func NewRecurrence(coefficients, seeds []int64) (*Recurrence, error) {
+	if len(coefficients) == 0 || len(coefficients) != len(seeds) {
+		return nil, fmt.Errorf("%w: %d coefficients and %d seeds", ErrInvalidRecurrence, len(coefficients), len(seeds))
+	}
+	if coefficients[0] < 1 {
+		return nil, fmt.Errorf("%w: first coefficient %d is less than 1", ErrInvalidRecurrence, coefficients[0])
+	}
+	for i := range coefficients {
+		if coefficients[i] < 0 || seeds[i] < 0 {
+			return nil, fmt.Errorf("%w: negative coefficient or seed", ErrInvalidRecurrence)
+		}
+	}
+	return &Recurrence{
+		coefficients: append([]int64(nil), coefficients...),
+		seeds:        append([]int64(nil), seeds...),
+	}, nil
+}
+
+func mustRecurrence(coefficients, seeds []int64) *Recurrence {
+	r, err := NewRecurrence(coefficients, seeds)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+var (
+	Fibonacci  = mustRecurrence([]int64{1, 1}, []int64{0, 1})       // 0, 1, 1, 2, 3, 5, 8, ...
+	Lucas      = mustRecurrence([]int64{1, 1}, []int64{2, 1})       // 2, 1, 3, 4, 7, 11, 18, ...
+	Pell       = mustRecurrence([]int64{2, 1}, []int64{0, 1})       // 0, 1, 2, 5, 12, 29, 70, ...
+	Tribonacci = mustRecurrence([]int64{1, 1, 1}, []int64{0, 0, 1}) // 0, 0, 1, 1, 2, 4, 7, 13, ...
+)
+
+// Order is the number of previous terms each term depends on
+func (r *Recurrence) Order() int {
+	return len(r.seeds)
+}
+
+// Terms yields the index and value of every term without limit, each value is a new *big.Int
+func (r *Recurrence) Terms() iter.Seq2[int, *big.Int] {
+	return func(yield func(int, *big.Int) bool) {
+		k := len(r.seeds)
+		last := make([]*big.Int, k) // last[i] is a(n-1-i)
+		for n, seed := range r.seeds {
+			last[k-1-n] = big.NewInt(seed)
+			if !yield(n, big.NewInt(seed)) {
+				return
+			}
+		}
+
+		c := make([]*big.Int, k)
+		for i, coefficient := range r.coefficients {
+			c[i] = big.NewInt(coefficient)
+		}
+		term := new(big.Int)
+		for n := k; ; n++ {
+			next := new(big.Int)
+			for i := range c {
+				next.Add(next, term.Mul(c[i], last[i]))
+			}
+			copy(last[1:], last[:k-1])
+			last[0] = next
+			if !yield(n, new(big.Int).Set(next)) {
+				return
+			}
+		}
+	}
+}
+
+// Nth returns a(n), it panics if n is negative
+func (r *Recurrence) Nth(n int) *big.Int {
+	if n < 0 {
+		panic("fib: negative index")
+	}
+	for i, a := range r.Terms() {
+		if i == n {
+			return a
+		}
+	}
+	panic("unreachable")
+}
+
+// Nth64 returns a(n), ok is false when it doesn't fit in an int64
+func (r *Recurrence) Nth64(n int) (a int64, ok bool) {
+	if n < 0 {
+		return 0, false
+	}
+	for i, term := range r.Terms() {
+		if !term.IsInt64() && i >= len(r.seeds)-1 {
+			return 0, false // the terms never decrease from here, so a(n) won't fit either
+		}
+		if i == n {
+			return term.Int64(), true
+		}
+	}
+	return 0, false
+}
+
+// Index returns the lowest n for which a(n) = x
+func (r *Recurrence) Index(x *big.Int) (int, bool) {
+	k := len(r.seeds)
+	var prev *big.Int
+	repeats := 0
+	for n, a := range r.Terms() {
+		cmp := a.Cmp(x)
+		if cmp == 0 {
+			return n, true
+		}
+		if cmp > 0 && n >= k-1 {
+			return 0, false // past x and never decreasing again
+		}
+
+		// k+1 equal terms in a row means the sequence is constant from here on
+		if n >= k && a.Cmp(prev) == 0 {
+			repeats++
+			if repeats == k {
+				return 0, false
+			}
+		} else {
+			repeats = 0
+		}
+		prev = a
+	}
+	return 0, false
+}
+
+// Contains reports whether x is a term of the sequence
+func (r *Recurrence) Contains(x *big.Int) bool {
+	_, ok := r.Index(x)
+	return ok
+}
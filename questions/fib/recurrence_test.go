@@ -0,0 +1,87 @@
+package fib
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var recurrenceTests = []struct {
+	name       string
+	recurrence *Recurrence
+	terms      []int64 // the start of the sequence, from OEIS
+	nonMembers []int64
+}{
+	{"fibonacci", Fibonacci, []int64{0, 1, 1, 2, 3, 5, 8, 13, 21, 34, 55, 89, 144}, []int64{-1, 4, 6, 7, 9, 100}},
+	{"lucas", Lucas, []int64{2, 1, 3, 4, 7, 11, 18, 29, 47, 76, 123, 199, 322}, []int64{-1, 0, 5, 6, 8, 100}},
+	{"pell", Pell, []int64{0, 1, 2, 5, 12, 29, 70, 169, 408, 985, 2378, 5741}, []int64{-1, 3, 4, 6, 13, 100}},
+	{"tribonacci", Tribonacci, []int64{0, 0, 1, 1, 2, 4, 7, 13, 24, 44, 81, 149, 274}, []int64{-1, 3, 5, 6, 8, 100}},
+	{"narayana cows", mustRecurrence([]int64{1, 0, 1}, []int64{1, 1, 1}), []int64{1, 1, 1, 2, 3, 4, 6, 9, 13, 19, 28, 41}, []int64{0, 5, 7, 8, 10}},
+	{"powers of 3", mustRecurrence([]int64{3}, []int64{1}), []int64{1, 3, 9, 27, 81, 243, 729}, []int64{0, 2, 4, 10, 28}},
+	{"constant", mustRecurrence([]int64{1, 0}, []int64{7, 7}), []int64{7, 7, 7, 7}, []int64{0, 6, 8}},
+}
+
+func TestRecurrence(t *testing.T) {
+	for _, tt := range recurrenceTests {
+		t.Run(tt.name, func(t *testing.T) {
+			for n, want := range tt.terms {
+				assert.Equal(t, big.NewInt(want), tt.recurrence.Nth(n), "a(%d)", n)
+
+				a, ok := tt.recurrence.Nth64(n)
+				assert.True(t, ok)
+				assert.Equal(t, want, a)
+
+				i, ok := tt.recurrence.Index(big.NewInt(want))
+				require.True(t, ok, "index of %d", want)
+				assert.Equal(t, want, tt.terms[i], "lowest index of %d", want)
+				assert.LessOrEqual(t, i, n)
+			}
+			for _, x := range tt.nonMembers {
+				assert.False(t, tt.recurrence.Contains(big.NewInt(x)), x)
+			}
+		})
+	}
+}
+
+func TestRecurrenceMatchesFib(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 50, 92, 93, 500} {
+		assert.Equal(t, Nth(n), Fibonacci.Nth(n), "F%d", n)
+	}
+
+	f, ok := Fibonacci.Nth64(92)
+	assert.True(t, ok)
+	assert.Equal(t, f92, f)
+	_, ok = Fibonacci.Nth64(93)
+	assert.False(t, ok)
+	_, ok = Fibonacci.Nth64(1_000_000)
+	assert.False(t, ok)
+
+	i, ok := Fibonacci.Index(Nth(500))
+	assert.True(t, ok)
+	assert.Equal(t, 500, i)
+	assert.False(t, Fibonacci.Contains(new(big.Int).Add(Nth(500), big.NewInt(1))))
+	assert.Panics(t, func() { Fibonacci.Nth(-1) })
+}
+
+func TestNewRecurrenceInvalid(t *testing.T) {
+	for _, tt := range []struct{ coefficients, seeds []int64 }{
+		{nil, nil},
+		{[]int64{1, 1}, []int64{0}},
+		{[]int64{0, 1}, []int64{0, 1}},
+		{[]int64{1, -1}, []int64{0, 1}},
+		{[]int64{1, 1}, []int64{-2, 1}},
+	} {
+		_, err := NewRecurrence(tt.coefficients, tt.seeds)
+		assert.ErrorIs(t, err, ErrInvalidRecurrence, "%v %v", tt.coefficients, tt.seeds)
+	}
+
+	// the recurrence keeps its own copy
+	c := []int64{1, 1}
+	r, err := NewRecurrence(c, []int64{0, 1})
+	require.NoError(t, err)
+	c[0] = 5
+	assert.Equal(t, big.NewInt(55), r.Nth(10))
+	assert.Equal(t, 2, r.Order())
+}
@@ -0,0 +1,94 @@
+package fib
+
+import (
+	"iter"
+	"math"
+	"math/big"
+	"slices"
+)
+
+// Sequence yields the index and value of each Fibonacci number that fits in an int64 (F0 to F92),
+// stopping rather than overflowing
+func Sequence() iter.Seq2[int, int64] {
+	return func(yield func(int, int64) bool) {
+		a, b := int64(0), int64(1)
+		last := false
+		for i := 0; ; i++ {
+			if !yield(i, a) || last {
+				return
+			}
+			if a > math.MaxInt64-b {
+				last = true // a+b would overflow, so b is the last one that fits
+				a = b
+				continue
+			}
+			a, b = b, a+b
+		}
+	}
+}
+
+// SequenceBig yields the index and value of every Fibonacci number without limit, each value is a new *big.Int
+func SequenceBig() iter.Seq2[int, *big.Int] {
+	return func(yield func(int, *big.Int) bool) {
+		a, b := big.NewInt(0), big.NewInt(1)
+		for i := 0; ; i++ {
+			if !yield(i, new(big.Int).Set(a)) {
+				return
+			}
+			a.Add(a, b)
+			a, b = b, a
+		}
+	}
+}
+
+// FibIndex returns the index of n in the sequence, the lower one for 1 (F1 = F2 = 1)
+func FibIndex(n int64) (int, bool) {
+	i, found := slices.BinarySearch(table(), n)
+	if !found {
+		return 0, false
+	}
+	return i, true
+}
+
+// FibIndexBig is FibIndex for any size of n. Fn is within rounding of φⁿ/√5, so the bit length of n
+// pins the index down to a couple of candidates, which are checked with Nth in O(log n) each
+// rather than walking the sequence.
+func FibIndexBig(n *big.Int) (int, bool) {
+	if n.IsInt64() {
+		return FibIndex(n.Int64())
+	}
+	if n.Sign() < 0 {
+		return 0, false
+	}
+
+	// 2^(b-1) <= n < 2^b, so log2(n) + log2(√5) = i·log2(φ) gives the range of i
+	b := float64(n.BitLen())
+	log2Sqrt5, log2Phi := math.Log2(math.Sqrt(5)), math.Log2(math.Phi)
+	lo := int((b-1+log2Sqrt5)/log2Phi) - 1
+	hi := int((b+log2Sqrt5)/log2Phi) + 1
+	for i := lo; i <= hi; i++ {
+		switch Nth(i).Cmp(n) {
+		case 0:
+			return i, true
+		case 1:
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// Nearest returns the closest Fibonacci numbers at or below and at or above n, both are n when it is one.
+// lo is -1 for a negative n and hi is -1 above F92, as there is no such int64 Fibonacci number.
+func Nearest(n int64) (lo, hi int64) {
+	t := table()
+	i, found := slices.BinarySearch(t, n)
+	switch {
+	case found:
+		return n, n
+	case i == 0:
+		return -1, t[0]
+	case i == len(t):
+		return t[len(t)-1], -1
+	}
+	return t[i-1], t[i]
+}
@@ -0,0 +1,123 @@
+package fib
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSequence(t *testing.T) {
+	var got []int64
+	for i, f := range Sequence() {
+		require.Equal(t, len(got), i)
+		got = append(got, f)
+	}
+	require.Len(t, got, 93) // F0 to F92
+	assert.Equal(t, []int64{0, 1, 1, 2, 3, 5, 8, 13}, got[:8])
+	assert.Equal(t, f92, got[92])
+	for i := 2; i < len(got); i++ {
+		assert.Equal(t, got[i-2]+got[i-1], got[i])
+	}
+
+	// stopping early
+	n := 0
+	for range Sequence() {
+		if n++; n == 5 {
+			break
+		}
+	}
+	assert.Equal(t, 5, n)
+}
+
+func TestSequenceBig(t *testing.T) {
+	var prev, cur *big.Int
+	for i, f := range SequenceBig() {
+		if i == 93 {
+			assert.Equal(t, "12200160415121876738", f.String())
+		}
+		if i >= 2 {
+			assert.Zero(t, new(big.Int).Add(prev, cur).Cmp(f), "F%d", i)
+		}
+		if i == 300 {
+			break
+		}
+		prev, cur = cur, f
+	}
+}
+
+func TestFibIndex(t *testing.T) {
+	tests := []struct {
+		n     int64
+		index int
+		ok    bool
+	}{
+		{0, 0, true},
+		{1, 1, true},
+		{2, 3, true},
+		{39088169, 38, true},
+		{f92, 92, true},
+		{4, 0, false},
+		{701408732, 0, false},
+		{-1, 0, false},
+		{math.MaxInt64, 0, false},
+	}
+	for _, tt := range tests {
+		index, ok := FibIndex(tt.n)
+		assert.Equal(t, tt.ok, ok, tt.n)
+		assert.Equal(t, tt.index, index, tt.n)
+	}
+}
+
+func TestFibIndexBig(t *testing.T) {
+	for _, i := range []int{0, 1, 3, 50, 92, 93, 94, 100, 1000, 4785, 10000} {
+		f := Nth(i)
+		got, ok := FibIndexBig(f)
+		require.True(t, ok, "F%d", i)
+		assert.Equal(t, i, got)
+
+		for _, delta := range []int64{-1, 1} {
+			_, ok = FibIndexBig(new(big.Int).Add(f, big.NewInt(delta)))
+			assert.Equal(t, i < 5 && IsFibonacciBig(new(big.Int).Add(f, big.NewInt(delta))), ok, "F%d%+d", i, delta)
+		}
+	}
+	for i, f := range SequenceBig() {
+		if i > 3000 {
+			break
+		}
+		got, ok := FibIndexBig(f)
+		require.True(t, ok, "F%d", i)
+		if i != 2 { // F1 = F2 = 1
+			require.Equal(t, i, got)
+		}
+	}
+
+	_, ok := FibIndexBig(big.NewInt(-5))
+	assert.False(t, ok)
+	_, ok = FibIndexBig(new(big.Int).Neg(Nth(100)))
+	assert.False(t, ok)
+}
+
+func TestNearest(t *testing.T) {
+	tests := []struct {
+		n, lo, hi int64
+	}{
+		{0, 0, 0},
+		{4, 3, 5},
+		{13, 13, 13},
+		{100, 89, 144},
+		{701408732, 433494437, 701408733}, // F43 and F44
+		{f92 - 1, 4660046610375530309, f92},
+		{f92, f92, f92},
+		{f92 + 1, f92, -1},
+		{math.MaxInt64, f92, -1},
+		{-5, -1, 0},
+	}
+	for _, tt := range tests {
+		lo, hi := Nearest(tt.n)
+		assert.Equal(t, tt.lo, lo, "lo %d", tt.n)
+		assert.Equal(t, tt.hi, hi, "hi %d", tt.n)
+	}
+}
@@ -0,0 +1,180 @@
+package fib
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+)
+
+var (
+	ErrNotPositive       = errors.New("fib: not a positive integer")
+	ErrInvalidZeckendorf = errors.New("fib: invalid Zeckendorf representation")
+	ErrCodeOverflow      = errors.New("fib: Fibonacci code overflows int64")
+)
+
+// Zeckendorf returns the unique set of non-consecutive Fibonacci numbers that sum to n, largest first,
+// e.g. 100 = 89 + 8 + 3. It returns nil when n is not positive.
+func Zeckendorf(n int64) []int64 {
+	var terms []int64
+	for _, k := range zeckendorfIndices(n) {
+		terms = append(terms, table()[k])
+	}
+	return terms
+}
+
+// zeckendorfIndices returns the indices (2 and up) of the Zeckendorf terms of n, largest first
+func zeckendorfIndices(n int64) []int {
+	var indices []int
+	t := table()
+	for n > 0 {
+		// greedy: the largest Fibonacci number <= n, which can't be the one after the previous term
+		k, found := slices.BinarySearch(t, n)
+		if !found {
+			k--
+		}
+		if k == 1 {
+			k = 2 // F1 and F2 are both 1, the representation uses F2
+		}
+		indices = append(indices, k)
+		n -= t[k]
+	}
+	return indices
+}
+
+// FromZeckendorf sums a Zeckendorf representation, it checks that terms is one: distinct
+// non-consecutive Fibonacci numbers (from F2 = 1), largest first
+func FromZeckendorf(terms []int64) (int64, error) {
+	var sum int64
+	prev := math.MaxInt
+	for _, term := range terms {
+		k, ok := FibIndex(term)
+		if term == 1 {
+			k = 2 // F1 and F2 are both 1, only F2 is used
+		}
+		if !ok || k < 2 || k >= prev-1 {
+			return 0, fmt.Errorf("%w: %v", ErrInvalidZeckendorf, terms)
+		}
+		prev = k
+		sum += term // can't overflow, the sum of non-consecutive terms is less than the next Fibonacci number
+	}
+	if len(terms) == 0 {
+		return 0, fmt.Errorf("%w: empty", ErrInvalidZeckendorf)
+	}
+	return sum, nil
+}
+
+// Encoder writes the Fibonacci code of positive integers to a bit stream: the Zeckendorf bits from F2
+// upwards followed by an extra 1, so every code ends in 11 (1 = 11, 2 = 011, 3 = 0011, 4 = 1011).
+// Bits are packed most significant first, Flush pads the last byte with zeros.
+type Encoder struct {
+	w    *bufio.Writer
+	buf  byte
+	bits uint
+}
+
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: bufio.NewWriter(w)}
+}
+
+func (e *Encoder) Encode(n int64) error {
+	if n <= 0 {
+		return fmt.Errorf("%w: %d", ErrNotPositive, n)
+	}
+
+	indices := zeckendorfIndices(n)
+	j := len(indices) - 1 // smallest first
+	for k := 2; k <= indices[0]; k++ {
+		bit := j >= 0 && indices[j] == k
+		if bit {
+			j--
+		}
+		if err := e.writeBit(bit); err != nil {
+			return err
+		}
+	}
+	return e.writeBit(true)
+}
+
+// Flush writes any partial byte, padded with zeros, and flushes the underlying writer
+func (e *Encoder) Flush() error {
+	if e.bits > 0 {
+		if err := e.w.WriteByte(e.buf << (8 - e.bits)); err != nil {
+			return err
+		}
+		e.buf, e.bits = 0, 0
+	}
+	return e.w.Flush()
+}
+
+func (e *Encoder) writeBit(bit bool) error {
+	e.buf <<= 1
+	if bit {
+		e.buf |= 1
+	}
+	e.bits++
+	if e.bits < 8 {
+		return nil
+	}
+	e.bits = 0
+	return e.w.WriteByte(e.buf)
+}
+
+// Decoder reads integers written by Encoder
+type Decoder struct {
+	r    *bufio.Reader
+	buf  byte
+	bits uint
+}
+
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: bufio.NewReader(r)}
+}
+
+// Decode returns the next integer, io.EOF when the stream ends cleanly (only padding left)
+// and io.ErrUnexpectedEOF when it ends part way through a code
+func (d *Decoder) Decode() (int64, error) {
+	var n int64
+	k := 2
+	prev, seenOne := false, false
+	for {
+		bit, err := d.readBit()
+		if err == io.EOF && !seenOne {
+			return 0, io.EOF // zero padding
+		}
+		if err == io.EOF {
+			return 0, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		if bit && prev {
+			return n, nil
+		}
+		if bit {
+			seenOne = true
+			f, ok := Nth64(k)
+			if !ok || n > math.MaxInt64-f {
+				return 0, ErrCodeOverflow
+			}
+			n += f
+		}
+		prev = bit
+		k++
+	}
+}
+
+func (d *Decoder) readBit() (bool, error) {
+	if d.bits == 0 {
+		b, err := d.r.ReadByte()
+		if err != nil {
+			return false, err
+		}
+		d.buf, d.bits = b, 8
+	}
+	d.bits--
+	return d.buf>>d.bits&1 == 1, nil
+}
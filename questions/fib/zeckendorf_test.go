@@ -0,0 +1,126 @@
+package fib
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZeckendorf(t *testing.T) {
+	assert.Equal(t, []int64{1}, Zeckendorf(1))
+	assert.Equal(t, []int64{3, 1}, Zeckendorf(4))
+	assert.Equal(t, []int64{89, 8, 3}, Zeckendorf(100))
+	assert.Equal(t, []int64{f92}, Zeckendorf(f92))
+	assert.Nil(t, Zeckendorf(0))
+	assert.Nil(t, Zeckendorf(-5))
+
+	for _, n := range []int64{1, 2, 3, 4, 5, 6, 7, 100, 1000, 123456789, f92 - 1, math.MaxInt64} {
+		terms := Zeckendorf(n)
+		sum, err := FromZeckendorf(terms)
+		require.NoError(t, err, "%d %v", n, terms)
+		assert.Equal(t, n, sum)
+	}
+	for n := int64(1); n <= 10000; n++ {
+		sum, err := FromZeckendorf(Zeckendorf(n))
+		require.NoError(t, err, n)
+		require.Equal(t, n, sum)
+	}
+}
+
+func TestFromZeckendorfInvalid(t *testing.T) {
+	for _, terms := range [][]int64{
+		nil,
+		{},
+		{0},
+		{4},       // not Fibonacci
+		{1, 1},    // repeated
+		{3, 2},    // consecutive
+		{1, 3},    // not largest first
+		{8, 3, 2}, // consecutive at the end
+		{-1},
+	} {
+		_, err := FromZeckendorf(terms)
+		assert.ErrorIs(t, err, ErrInvalidZeckendorf, "%v", terms)
+	}
+}
+
+// bitString renders what Encoder wrote as a string of 0s and 1s, n bits long
+func bitString(b []byte, n int) string {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		sb.WriteByte('0' + b[i/8]>>(7-i%8)&1)
+	}
+	return sb.String()
+}
+
+func TestEncoder(t *testing.T) {
+	tests := []struct {
+		n    int64
+		code string
+	}{
+		{1, "11"},
+		{2, "011"},
+		{3, "0011"},
+		{4, "1011"},
+		{5, "00011"},
+		{11, "001011"},
+		{100, "00101000011"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		enc := NewEncoder(&buf)
+		require.NoError(t, enc.Encode(tt.n))
+		require.NoError(t, enc.Flush())
+		assert.Equal(t, (len(tt.code)+7)/8, buf.Len(), tt.n)
+		assert.Equal(t, tt.code, bitString(buf.Bytes(), len(tt.code)), tt.n)
+	}
+
+	enc := NewEncoder(io.Discard)
+	assert.ErrorIs(t, enc.Encode(0), ErrNotPositive)
+	assert.ErrorIs(t, enc.Encode(-1), ErrNotPositive)
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	values := []int64{1, 2, 3, 4, 5, 100, 1, 1, 7, 65535, 123456789, f92, math.MaxInt64}
+	for n := int64(1); n < 500; n++ {
+		values = append(values, n)
+	}
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	for _, n := range values {
+		require.NoError(t, enc.Encode(n))
+	}
+	require.NoError(t, enc.Flush())
+
+	dec := NewDecoder(&buf)
+	for _, want := range values {
+		n, err := dec.Decode()
+		require.NoError(t, err)
+		require.Equal(t, want, n)
+	}
+	_, err := dec.Decode()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestDecoderErrors(t *testing.T) {
+	_, err := NewDecoder(bytes.NewReader(nil)).Decode()
+	assert.Equal(t, io.EOF, err)
+
+	// 1011 then the start of 100 cut off
+	dec := NewDecoder(bytes.NewReader([]byte{0b1011_0010}))
+	n, err := dec.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), n)
+	_, err = dec.Decode()
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+
+	// 1010... with no terminating 11 for longer than any int64 code
+	_, err = NewDecoder(bytes.NewReader(bytes.Repeat([]byte{0b1010_1010}, 16))).Decode()
+	assert.ErrorIs(t, err, ErrCodeOverflow)
+}
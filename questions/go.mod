@@ -1,5 +1,10 @@
 module greystonetec.co.za/tests/go-candidate-test
 
-go 1.15
+go 1.23
 
-require github.com/stretchr/testify v1.9.0
+require (
+	github.com/go-playground/locales v0.14.1
+	github.com/go-playground/universal-translator v0.18.1
+	github.com/go-playground/validator/v10 v10.22.0
+	github.com/stretchr/testify v1.9.0
+)
@@ -0,0 +1,86 @@
+// Package luhn implements the Luhn (mod 10) checksum used by SA ID numbers, card PANs and IMEIs.
+//
+// Inputs are digit strings of any length. None of the functions allocate unless they return an error
+// (or, for Append, the result).
+package luhn
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNoDigits         = errors.New("luhn: no digits")
+	ErrInvalidCharacter = errors.New("luhn: invalid character")
+)
+
+// Checker holds the input rules, the zero value only accepts ASCII digits
+type Checker struct {
+	// SkipSeparators ignores spaces and hyphens, e.g. "4111 1111 1111 1111"
+	SkipSeparators bool
+}
+
+// Validate reports whether s is at least 2 digits and its last digit is a correct check digit
+func Validate(s string) bool {
+	return Checker{}.Validate(s)
+}
+
+// CheckDigit returns the digit that should be appended to payload to make it valid
+func CheckDigit(payload string) (int, error) {
+	return Checker{}.CheckDigit(payload)
+}
+
+// Append returns payload with its check digit appended
+func Append(payload string) (string, error) {
+	return Checker{}.Append(payload)
+}
+
+func (c Checker) Validate(s string) bool {
+	sum, digits, err := c.sum(s, false)
+	return err == nil && digits >= 2 && sum%10 == 0
+}
+
+func (c Checker) CheckDigit(payload string) (int, error) {
+	sum, digits, err := c.sum(payload, true) // the check digit will take the undoubled position
+	if err != nil {
+		return 0, err
+	}
+	if digits == 0 {
+		return 0, ErrNoDigits
+	}
+	return (10 - sum%10) % 10, nil
+}
+
+func (c Checker) Append(payload string) (string, error) {
+	d, err := c.CheckDigit(payload)
+	if err != nil {
+		return "", err
+	}
+	return payload + string(rune('0'+d)), nil
+}
+
+// sum adds up the digits of s from the right, doubling every second one starting with the
+// rightmost digit if dbl is set
+func (c Checker) sum(s string, dbl bool) (sum, digits int, err error) {
+	for i := len(s) - 1; i >= 0; i-- {
+		ch := s[i]
+		if ch < '0' || ch > '9' {
+			if c.SkipSeparators && (ch == ' ' || ch == '-') {
+				continue
+			}
+			return 0, 0, fmt.Errorf("%w %q at position %d", ErrInvalidCharacter, ch, i)
+		}
+
+		n := int(ch - '0')
+		if dbl {
+			n *= 2 // find product
+			if n > 9 {
+				n -= 9
+			}
+		}
+		sum += n
+		digits++
+		dbl = !dbl
+	}
+	return sum, digits, nil
+}
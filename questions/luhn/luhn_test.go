@@ -0,0 +1,97 @@
+package luhn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		in    string
+		valid bool
+	}{
+		{"9103105017084", true},    // SA ID from the question
+		{"4111111111111111", true}, // Visa test PAN
+		{"490154203237518", true},  // IMEI
+		{"79927398713", true},
+		{"18", true},
+		{"79927398710", false},
+		{"9103105017083", false},
+		{"0", false}, // no payload
+		{"", false},
+		{"4111 1111 1111 1111", false}, // separators need SkipSeparators
+		{"41a1", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.valid, Validate(tt.in), tt.in)
+	}
+}
+
+func TestValidateSkipSeparators(t *testing.T) {
+	c := Checker{SkipSeparators: true}
+	assert.True(t, c.Validate("4111 1111 1111 1111"))
+	assert.True(t, c.Validate("910310-5017-0-84"))
+	assert.False(t, c.Validate("4111 1111 1111 1112"))
+	assert.False(t, c.Validate("4111_1111_1111_1111"))
+	assert.False(t, c.Validate(" - "))
+}
+
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		payload string
+		digit   int
+	}{
+		{"910310501708", 4},
+		{"411111111111111", 1},
+		{"49015420323751", 8},
+		{"7992739871", 3},
+		{"0", 0},
+	}
+	for _, tt := range tests {
+		d, err := CheckDigit(tt.payload)
+		require.NoError(t, err, tt.payload)
+		assert.Equal(t, tt.digit, d, tt.payload)
+	}
+
+	_, err := CheckDigit("")
+	assert.ErrorIs(t, err, ErrNoDigits)
+	_, err = CheckDigit("12x4")
+	assert.ErrorIs(t, err, ErrInvalidCharacter)
+}
+
+func TestAppend(t *testing.T) {
+	s, err := Append("910310501708")
+	require.NoError(t, err)
+	assert.Equal(t, "9103105017084", s)
+
+	s, err = Checker{SkipSeparators: true}.Append("4111 1111 1111 111")
+	require.NoError(t, err)
+	assert.Equal(t, "4111 1111 1111 1111", s)
+	assert.True(t, Checker{SkipSeparators: true}.Validate(s))
+}
+
+func TestDoesNotAllocate(t *testing.T) {
+	c := Checker{SkipSeparators: true}
+	allocs := testing.AllocsPerRun(100, func() {
+		Validate("4111111111111111")
+		c.Validate("4111 1111 1111 1111")
+		_, _ = CheckDigit("411111111111111")
+	})
+	assert.Zero(t, allocs)
+}
+
+func BenchmarkValidate(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Validate("9103105017084")
+	}
+}
+
+func BenchmarkCheckDigit(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = CheckDigit("910310501708")
+	}
+}
@@ -0,0 +1,43 @@
+package nationalid
+
+// Botswana parses 9 digit Omang numbers. The 5th digit is 1 for male and 2 for female,
+// the number carries no date of birth and an Omang is only issued to citizens.
+// Reasons are "length", "non_numeric" and "invalid_gender".
+type Botswana struct{}
+
+func NewBotswana() *Botswana {
+	return &Botswana{}
+}
+
+func (*Botswana) Country() string {
+	return "BW"
+}
+
+func (bw *Botswana) Parse(id string) Identity {
+	identity := Identity{Country: bw.Country(), Number: id}
+
+	if len(id) != 9 {
+		identity.Reason = "length"
+		return identity
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '0' || id[i] > '9' {
+			identity.Reason = "non_numeric"
+			return identity
+		}
+	}
+
+	switch id[4] {
+	case '1':
+		identity.Gender = Male
+	case '2':
+		identity.Gender = Female
+	default:
+		identity.Reason = "invalid_gender"
+		return identity
+	}
+
+	identity.Valid = true
+	identity.Citizenship = Citizen
+	return identity
+}
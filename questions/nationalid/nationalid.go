@@ -0,0 +1,118 @@
+// Package nationalid parses national ID numbers of different countries into a common Identity,
+// with parsers looked up by ISO 3166-1 alpha-2 country code.
+package nationalid
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Gender string
+
+const (
+	GenderUnknown Gender = "" // the format doesn't encode it
+	Male          Gender = "M"
+	Female        Gender = "F"
+)
+
+type Citizenship string
+
+const (
+	CitizenshipUnknown Citizenship = "" // the format doesn't encode it
+	Citizen            Citizenship = "citizen"
+	PermanentResident  Citizenship = "permanent_resident"
+	Refugee            Citizenship = "refugee"
+)
+
+// Identity is what can be read from an ID number, fields the format doesn't encode are left zero
+type Identity struct {
+	Country     string // ISO 3166-1 alpha-2, e.g. "ZA"
+	Number      string // the ID number as given
+	DateOfBirth time.Time
+	Gender      Gender
+	Citizenship Citizenship
+	Valid       bool
+	Reason      string // stable code for why the number is invalid, "" when valid
+}
+
+// NationalIDParser parses the ID numbers of one country, Parse never fails, an invalid
+// number is reported through Identity.Valid and Identity.Reason
+type NationalIDParser interface {
+	Country() string
+	Parse(id string) Identity
+}
+
+var (
+	ErrUnknownCountry   = errors.New("no parser for country")
+	ErrDuplicateCountry = errors.New("parser already registered for country")
+)
+
+// Registry holds one parser per country and is safe for concurrent use
+type Registry struct {
+	parsers map[string]NationalIDParser
+	mu      sync.RWMutex
+}
+
+func NewRegistry(parsers ...NationalIDParser) *Registry {
+	r := &Registry{
+		parsers: make(map[string]NationalIDParser),
+	}
+	for _, p := range parsers {
+		if err := r.Register(p); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Default has a parser for every country in this package
+var Default = NewRegistry(NewSouthAfrica(), NewBotswana())
+
+// Register adds p under its country code (case-insensitive)
+func (r *Registry) Register(p NationalIDParser) error {
+	country := strings.ToUpper(p.Country())
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.parsers[country]; exists {
+		return fmt.Errorf("%w %s", ErrDuplicateCountry, country)
+	}
+	r.parsers[country] = p
+	return nil
+}
+
+func (r *Registry) Lookup(country string) (NationalIDParser, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, exists := r.parsers[strings.ToUpper(country)]
+	return p, exists
+}
+
+// Parse parses id with the parser for country, the error is only for an unknown country
+func (r *Registry) Parse(country, id string) (Identity, error) {
+	p, exists := r.Lookup(country)
+	if !exists {
+		return Identity{}, fmt.Errorf("%w %q", ErrUnknownCountry, country)
+	}
+	return p.Parse(id), nil
+}
+
+// Countries returns the registered country codes in order
+func (r *Registry) Countries() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	countries := make([]string, 0, len(r.parsers))
+	for country := range r.parsers {
+		countries = append(countries, country)
+	}
+	sort.Strings(countries)
+	return countries
+}
+
+// Parse parses id with the Default registry
+func Parse(country, id string) (Identity, error) {
+	return Default.Parse(country, id)
+}
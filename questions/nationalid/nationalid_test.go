@@ -0,0 +1,81 @@
+package nationalid
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"greystonetec.co.za/tests/go-candidate-test/said"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		country  string
+		id       string
+		identity Identity
+	}{
+		{"ZA", "9103105017084", Identity{
+			Country: "ZA", Number: "9103105017084", Valid: true, Gender: Male, Citizenship: Citizen,
+			DateOfBirth: time.Date(1991, time.March, 10, 0, 0, 0, 0, time.UTC),
+		}},
+		{"za", "9103105017282", Identity{
+			Country: "ZA", Number: "9103105017282", Valid: true, Gender: Male, Citizenship: Refugee,
+			DateOfBirth: time.Date(1991, time.March, 10, 0, 0, 0, 0, time.UTC),
+		}},
+		{"ZA", "9103105017083", Identity{Country: "ZA", Number: "9103105017083", Reason: "checksum"}},
+		{"BW", "123415678", Identity{Country: "BW", Number: "123415678", Valid: true, Gender: Male, Citizenship: Citizen}},
+		{"BW", "123425678", Identity{Country: "BW", Number: "123425678", Valid: true, Gender: Female, Citizenship: Citizen}},
+		{"BW", "123435678", Identity{Country: "BW", Number: "123435678", Reason: "invalid_gender"}},
+		{"BW", "12341567", Identity{Country: "BW", Number: "12341567", Reason: "length"}},
+		{"BW", "1234x5678", Identity{Country: "BW", Number: "1234x5678", Reason: "non_numeric"}},
+	}
+	for _, tt := range tests {
+		identity, err := Parse(tt.country, tt.id)
+		require.NoError(t, err)
+		assert.Equal(t, tt.identity, identity, "%s %s", tt.country, tt.id)
+	}
+}
+
+func TestParseUnknownCountry(t *testing.T) {
+	_, err := Parse("NA", "12345678901")
+	assert.ErrorIs(t, err, ErrUnknownCountry)
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	za := NewSouthAfrica(said.WithClock(func() time.Time {
+		return time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+	}))
+	require.NoError(t, r.Register(za))
+	assert.ErrorIs(t, r.Register(NewSouthAfrica()), ErrDuplicateCountry)
+
+	p, ok := r.Lookup("za")
+	require.True(t, ok)
+	assert.Same(t, za, p)
+
+	_, ok = r.Lookup("BW")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"ZA"}, r.Countries())
+	assert.Equal(t, []string{"BW", "ZA"}, Default.Countries())
+}
+
+func TestRegistryConcurrentUse(t *testing.T) {
+	r := NewRegistry(NewSouthAfrica())
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, _ = r.Parse("ZA", "9103105017084")
+		}()
+		go func() {
+			defer wg.Done()
+			_ = r.Register(NewBotswana())
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, []string{"BW", "ZA"}, r.Countries())
+}
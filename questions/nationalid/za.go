@@ -0,0 +1,45 @@
+package nationalid
+
+import "greystonetec.co.za/tests/go-candidate-test/said"
+
+// SouthAfrica parses 13 digit YYMMDDSSSSCAZ ID numbers, see package said.
+// Reasons are the said.ErrorCode values, e.g. "checksum".
+type SouthAfrica struct {
+	parser *said.SAIDParser
+}
+
+func NewSouthAfrica(opts ...said.SAIDParserOption) *SouthAfrica {
+	return &SouthAfrica{
+		parser: said.NewSAIDParser(opts...),
+	}
+}
+
+func (*SouthAfrica) Country() string {
+	return "ZA"
+}
+
+func (za *SouthAfrica) Parse(id string) Identity {
+	identity := Identity{Country: za.Country(), Number: id}
+
+	var details said.SAIDDetails
+	if err := za.parser.ParseInto(id, &details); err != nil {
+		identity.Reason = said.ErrorCode(err)
+		return identity
+	}
+
+	identity.Valid = true
+	identity.DateOfBirth = details.DateOfBirth
+	identity.Gender = Female
+	if details.Gender == said.Male {
+		identity.Gender = Male
+	}
+	switch details.Citizenship {
+	case said.Citizen:
+		identity.Citizenship = Citizen
+	case said.PermanentResident:
+		identity.Citizenship = PermanentResident
+	case said.Refugee:
+		identity.Citizenship = Refugee
+	}
+	return identity
+}
@@ -0,0 +1,235 @@
+// Package numsvc serves the number helpers (Fibonacci membership, Luhn) over HTTP as stateless
+// JSON batch endpoints:
+//
+//	POST /fib/check   [1, 4, "12200160415121876738"] -> {"results": [{"number": 1, "fibonacci": true, "index": 1}, ...]}
+//	POST /luhn/check  ["79927398713", ...]           -> {"results": [{"number": "79927398713", "valid": true}, ...]}
+//
+// Fibonacci numbers may be JSON numbers or strings holding them, in any form that is an integer
+// (1e3 but not 1.5), of any size up to the digit limit. Luhn numbers must be strings. Each item is
+// echoed back as it was sent. Problems with a single item (e.g. "abc" or true) are reported in its
+// result, problems with the request as a whole (not a JSON array, over a limit) get a 4xx status
+// and {"error": "..."}.
+package numsvc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"greystonetec.co.za/tests/go-candidate-test/fib"
+	"greystonetec.co.za/tests/go-candidate-test/luhn"
+)
+
+const (
+	DefaultMaxBodyBytes = 1 << 20
+	DefaultMaxItems     = 1000
+	DefaultMaxDigits    = 1000
+)
+
+// Server is an http.Handler for the endpoints, it holds no state besides its limits
+type Server struct {
+	maxBodyBytes int64
+	maxItems     int
+	maxDigits    int
+	mux          *http.ServeMux
+}
+
+type Option func(*Server)
+
+// WithMaxBodyBytes limits the request body size, larger requests get 413
+func WithMaxBodyBytes(n int64) Option {
+	return func(s *Server) {
+		s.maxBodyBytes = n
+	}
+}
+
+// WithMaxItems limits the number of items in a batch, larger batches get 413
+func WithMaxItems(n int) Option {
+	return func(s *Server) {
+		s.maxItems = n
+	}
+}
+
+// WithMaxDigits limits the length of each number, which bounds the work a single item can cause
+func WithMaxDigits(n int) Option {
+	return func(s *Server) {
+		s.maxDigits = n
+	}
+}
+
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		maxBodyBytes: DefaultMaxBodyBytes,
+		maxItems:     DefaultMaxItems,
+		maxDigits:    DefaultMaxDigits,
+		mux:          http.NewServeMux(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.mux.HandleFunc("POST /fib/check", s.fibCheck)
+	s.mux.HandleFunc("POST /luhn/check", s.luhnCheck)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+type FibResult struct {
+	Number    json.RawMessage `json:"number"`
+	Fibonacci bool            `json:"fibonacci"`
+	Index     *int            `json:"index,omitempty"` // the lowest index, 1 for 1
+	Error     string          `json:"error,omitempty"`
+}
+
+type LuhnResult struct {
+	Number json.RawMessage `json:"number"`
+	Valid  bool            `json:"valid"`
+	Error  string          `json:"error,omitempty"`
+}
+
+type response[T any] struct {
+	Results []T `json:"results"`
+}
+
+func (s *Server) fibCheck(w http.ResponseWriter, r *http.Request) {
+	numbers, ok := decodeBatch[json.RawMessage](s, w, r)
+	if !ok {
+		return
+	}
+
+	results := make([]FibResult, len(numbers))
+	for i, number := range numbers {
+		results[i] = s.checkFib(number)
+	}
+	writeJSON(w, http.StatusOK, response[FibResult]{results})
+}
+
+func (s *Server) checkFib(number json.RawMessage) FibResult {
+	result := FibResult{Number: number}
+
+	// a string holds the number as text, anything else is taken as written
+	text := string(number)
+	var str string
+	if json.Unmarshal(number, &str) == nil {
+		text = str
+	}
+	n, err := parseInteger(text, s.maxDigits)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	index, ok := fib.FibIndexBig(n)
+	if result.Fibonacci = ok; ok {
+		result.Index = &index
+	}
+	return result
+}
+
+func (s *Server) luhnCheck(w http.ResponseWriter, r *http.Request) {
+	numbers, ok := decodeBatch[json.RawMessage](s, w, r)
+	if !ok {
+		return
+	}
+
+	results := make([]LuhnResult, len(numbers))
+	for i, number := range numbers {
+		results[i] = LuhnResult{Number: number}
+		var str string
+		if err := json.Unmarshal(number, &str); err != nil {
+			results[i].Error = "not a string" // a JSON number would lose leading zeros
+			continue
+		}
+		results[i].Valid = luhn.Validate(str)
+	}
+	writeJSON(w, http.StatusOK, response[LuhnResult]{results})
+}
+
+// decodeBatch reads a JSON array from the body within the limits, writing the error response if it can't
+func decodeBatch[T any](s *Server, w http.ResponseWriter, r *http.Request) ([]T, bool) {
+	var items []T
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.maxBodyBytes)).Decode(&items)
+
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body larger than %d bytes", maxBytesErr.Limit))
+		return nil, false
+	case err != nil:
+		writeError(w, http.StatusBadRequest, "request body must be a JSON array: "+err.Error())
+		return nil, false
+	case len(items) > s.maxItems:
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("more than %d items", s.maxItems))
+		return nil, false
+	}
+	return items, true
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, struct {
+		Error string `json:"error"`
+	}{msg})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+var (
+	errNotANumber   = errors.New("not a number")
+	errNotAnInteger = errors.New("not an integer")
+)
+
+// numberPattern is the JSON number grammar: sign, integer part, fraction and exponent
+var numberPattern = regexp.MustCompile(`^(-?)(0|[1-9][0-9]*)(?:\.([0-9]+))?(?:[eE]([+-]?[0-9]+))?$`)
+
+// parseInteger parses a JSON number that is an integer, however it is written (10, 1e1, 10.0, 0.1e2).
+// The work is bounded by the length of text and maxDigits, a large exponent is rejected before
+// any zeros are written out.
+func parseInteger(text string, maxDigits int) (*big.Int, error) {
+	m := numberPattern.FindStringSubmatch(text)
+	if m == nil {
+		return nil, errNotANumber
+	}
+	sign, frac, expText := m[1], m[3], m[4]
+	digits := strings.TrimLeft(m[2]+frac, "0")
+	if digits == "" {
+		return new(big.Int), nil // 0, -0.0, 0e99, ...
+	}
+
+	tooManyDigits := fmt.Errorf("more than %d digits", maxDigits)
+	exp := 0
+	if expText != "" {
+		var err error
+		exp, err = strconv.Atoi(expText)
+		if err != nil || exp > maxDigits || exp < -len(text) {
+			if strings.HasPrefix(expText, "-") {
+				return nil, errNotAnInteger // a fraction of a non-zero number
+			}
+			return nil, tooManyDigits
+		}
+	}
+	exp -= len(frac)
+
+	if exp < 0 {
+		cut := -exp
+		if cut > len(digits) || strings.Trim(digits[len(digits)-cut:], "0") != "" {
+			return nil, errNotAnInteger
+		}
+		digits, exp = digits[:len(digits)-cut], 0
+	}
+	if len(digits)+exp > maxDigits {
+		return nil, tooManyDigits
+	}
+	n, _ := new(big.Int).SetString(sign+digits+strings.Repeat("0", exp), 10)
+	return n, nil
+}
@@ -0,0 +1,183 @@
+package numsvc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"greystonetec.co.za/tests/go-candidate-test/fib"
+)
+
+func post(t testing.TB, h http.Handler, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+	return rec
+}
+
+func TestFibCheck(t *testing.T) {
+	rec := post(t, NewServer(), "/fib/check",
+		`[0, 1, 4, 144, -8, 1.5, "7540113804746346429", "12200160415121876738", "12200160415121876739"]`)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"results": [
+		{"number": 0, "fibonacci": true, "index": 0},
+		{"number": 1, "fibonacci": true, "index": 1},
+		{"number": 4, "fibonacci": false},
+		{"number": 144, "fibonacci": true, "index": 12},
+		{"number": -8, "fibonacci": false},
+		{"number": 1.5, "fibonacci": false, "error": "not an integer"},
+		{"number": "7540113804746346429", "fibonacci": true, "index": 92},
+		{"number": "12200160415121876738", "fibonacci": true, "index": 93},
+		{"number": "12200160415121876739", "fibonacci": false}
+	]}`, rec.Body.String())
+}
+
+func TestFibCheckItemErrors(t *testing.T) { // a bad item doesn't fail the rest of the batch
+	rec := post(t, NewServer(), "/fib/check",
+		`["abc", true, null, {}, 1e3, "1.44E2", 144.0, 0.1e1, -0e5, "1e-1", "1e999999999999", "", " 8", 8]`)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"results": [
+		{"number": "abc", "fibonacci": false, "error": "not a number"},
+		{"number": true, "fibonacci": false, "error": "not a number"},
+		{"number": null, "fibonacci": false, "error": "not a number"},
+		{"number": {}, "fibonacci": false, "error": "not a number"},
+		{"number": 1e3, "fibonacci": false},
+		{"number": "1.44E2", "fibonacci": true, "index": 12},
+		{"number": 144.0, "fibonacci": true, "index": 12},
+		{"number": 0.1e1, "fibonacci": true, "index": 1},
+		{"number": -0e5, "fibonacci": true, "index": 0},
+		{"number": "1e-1", "fibonacci": false, "error": "not an integer"},
+		{"number": "1e999999999999", "fibonacci": false, "error": "more than 1000 digits"},
+		{"number": "", "fibonacci": false, "error": "not a number"},
+		{"number": " 8", "fibonacci": false, "error": "not a number"},
+		{"number": 8, "fibonacci": true, "index": 6}
+	]}`, rec.Body.String())
+}
+
+func TestParseInteger(t *testing.T) {
+	tests := map[string]string{
+		"0": "0", "-0.0": "0", "0e99999999999": "0", "12": "12", "-12": "-12", "1.2e1": "12",
+		"1200e-2": "12", "1.000000": "1", "5e20": "500000000000000000000",
+	}
+	for in, want := range tests {
+		n, err := parseInteger(in, 30)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, n.String(), in)
+	}
+
+	for in, want := range map[string]string{
+		"1.5": "not an integer", "12e-3": "not an integer", "1e-99999999999": "not an integer",
+		"1e30": "more than 30 digits", "1e99999999999": "more than 30 digits",
+		"01": "not a number", "+1": "not a number", "1.": "not a number", ".5": "not a number", "0x10": "not a number",
+	} {
+		_, err := parseInteger(in, 30)
+		assert.EqualError(t, err, want, in)
+	}
+}
+
+func TestLuhnCheck(t *testing.T) {
+	rec := post(t, NewServer(), "/luhn/check", `["79927398713", "79927398710", "9103105017084", "7992-7398-713", ""]`)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"results": [
+		{"number": "79927398713", "valid": true},
+		{"number": "79927398710", "valid": false},
+		{"number": "9103105017084", "valid": true},
+		{"number": "7992-7398-713", "valid": false},
+		{"number": "", "valid": false}
+	]}`, rec.Body.String())
+
+	rec = post(t, NewServer(), "/luhn/check", `[79927398713, "79927398713"]`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"results": [
+		{"number": 79927398713, "valid": false, "error": "not a string"},
+		{"number": "79927398713", "valid": true}
+	]}`, rec.Body.String())
+}
+
+func TestLimits(t *testing.T) {
+	s := NewServer(WithMaxBodyBytes(64), WithMaxItems(3), WithMaxDigits(5))
+
+	rec := post(t, s, "/fib/check", "["+strings.Repeat("1,", 40)+"1]")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.JSONEq(t, `{"error": "request body larger than 64 bytes"}`, rec.Body.String())
+
+	rec = post(t, s, "/luhn/check", `["1", "2", "3", "4"]`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.JSONEq(t, `{"error": "more than 3 items"}`, rec.Body.String())
+
+	rec = post(t, s, "/fib/check", `[123456]`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"results": [{"number": 123456, "fibonacci": false, "error": "more than 5 digits"}]}`, rec.Body.String())
+}
+
+func TestBadRequests(t *testing.T) {
+	s := NewServer()
+	for _, body := range []string{``, `{}`, `[1, 2`, `"abc"`} {
+		rec := post(t, s, "/fib/check", body)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+		assert.Contains(t, rec.Body.String(), `"error"`, body)
+	}
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fib/check", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	rec = post(t, s, "/nope", `[]`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+// Benchmarks go through a real loopback server so the numbers include JSON and HTTP overhead,
+// run with go test -bench . ./numsvc
+func BenchmarkFibCheck(b *testing.B) {
+	benchmarkBatches(b, "/fib/check", func(i int) any { return i * 7919 })
+}
+
+// BenchmarkFibCheckLarge sends members of 800-odd digits, the costliest items under the default limits
+func BenchmarkFibCheckLarge(b *testing.B) {
+	benchmarkBatches(b, "/fib/check", func(i int) any { return fib.Nth(4000 + i%100).String() })
+}
+
+func BenchmarkLuhnCheck(b *testing.B) {
+	benchmarkBatches(b, "/luhn/check", func(i int) any { return fmt.Sprintf("%013d", 9103105017084+i) })
+}
+
+func benchmarkBatches(b *testing.B, path string, item func(i int) any) {
+	srv := httptest.NewServer(NewServer())
+	defer srv.Close()
+
+	for _, size := range []int{1, 100, 1000} {
+		items := make([]any, size)
+		for i := range items {
+			items[i] = item(i)
+		}
+		body, err := json.Marshal(items)
+		require.NoError(b, err)
+
+		b.Run(fmt.Sprintf("items=%d", size), func(b *testing.B) {
+			b.SetBytes(int64(len(body)))
+			for i := 0; i < b.N; i++ {
+				resp, err := srv.Client().Post(srv.URL+path, "application/json", bytes.NewReader(body))
+				if err != nil {
+					b.Fatal(err)
+				}
+				_, _ = io.Copy(io.Discard, resp.Body) // so the connection is reused
+				_ = resp.Body.Close()
+				if resp.StatusCode != http.StatusOK {
+					b.Fatal(resp.Status)
+				}
+			}
+		})
+	}
+}
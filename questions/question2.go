@@ -1,14 +1,21 @@
 package main
 
+import "math"
+
+// IsFibNumber reports whether valToCheck is in the sequence that continues from previousValue, currentValue,
+// use fib.IsFibonacci to check against the Fibonacci numbers without seeds
 func IsFibNumber(valToCheck, previousValue, currentValue int) bool {
 	if valToCheck < 0 {
 		return false
 	}
-	if valToCheck == currentValue {
-		return true
-	}
-	if currentValue > valToCheck {
-		return false
+	for currentValue < valToCheck {
+		if previousValue <= 0 && currentValue <= 0 {
+			return false // every later value is a sum of these, so it never grows, e.g. seeds 0, 0
+		}
+		if currentValue > 0 && previousValue > math.MaxInt-currentValue {
+			return false // the next value overflows, so valToCheck falls between currentValue and it
+		}
+		previousValue, currentValue = currentValue, previousValue+currentValue
 	}
-	return IsFibNumber(valToCheck, currentValue, previousValue+currentValue)
+	return currentValue == valToCheck
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,3 +14,18 @@ func Test_IsGoodFibNumberPasses(t *testing.T) {
 func Test_IsBadFibNumberFailes(t *testing.T) {
 	assert.False(t, IsFibNumber(701408732, 0, 1))
 }
+
+func Test_IsFibNumberDoesNotOverflow(t *testing.T) {
+	assert.True(t, IsFibNumber(7540113804746346429, 0, 1)) // largest Fibonacci int64
+	assert.False(t, IsFibNumber(7540113804746346430, 0, 1))
+	assert.False(t, IsFibNumber(math.MaxInt64, 0, 1))
+}
+
+func Test_IsFibNumberDegenerateSeeds(t *testing.T) {
+	assert.False(t, IsFibNumber(5, 0, 0))
+	assert.True(t, IsFibNumber(0, 0, 0))
+	assert.False(t, IsFibNumber(5, -3, -2))
+	assert.False(t, IsFibNumber(5, -20, 6)) // -20, 6, -14, -8, ...
+	assert.True(t, IsFibNumber(9, -7, 5))   // -7, 5, -2, 3, 1, 4, 5, 9
+	assert.False(t, IsFibNumber(math.MaxInt64, math.MinInt64, -1))
+}
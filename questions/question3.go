@@ -2,7 +2,11 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"math/rand"
+	"runtime"
 	"sync"
 	"time"
 )
@@ -27,15 +31,74 @@ func newRequestId(idLen int) string {
 type State string
 
 var (
-	StateUnknown  State = "Unknown"
-	StateNew      State = "New"
-	StateBusy     State = "Busy"
-	StateFinished State = "Finished"
+	StateUnknown   State = "Unknown"
+	StateNew       State = "New"
+	StateBusy      State = "Busy"
+	StateFinished  State = "Finished"
+	StateFailed    State = "Failed"    // the handler returned an error
+	StateCancelled State = "Cancelled" // by CancelRequest or the context given to QueueRequest
+	StateTimedOut  State = "TimedOut"  // its deadline passed before it finished
 )
 
+// transitions lists the states each state can move to, requests start New when queued and
+// Finished, Failed, Cancelled and TimedOut are final
+var transitions = map[State][]State{
+	StateNew:  {StateBusy, StateCancelled, StateTimedOut},
+	StateBusy: {StateFinished, StateFailed, StateCancelled, StateTimedOut},
+}
+
+func (s State) CanTransitionTo(to State) bool {
+	for _, next := range transitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Terminal reports whether the request can no longer change state
+func (s State) Terminal() bool {
+	return s != StateUnknown && len(transitions[s]) == 0
+}
+
+var (
+	ErrRequestCancelled  = errors.New("request cancelled")
+	ErrRequestTimedOut   = errors.New("request timed out")
+	ErrUnknownRequest    = errors.New("unknown request")
+	ErrIllegalTransition = errors.New("illegal state transition")
+)
+
+// TransitionError is returned for a change of state the state machine doesn't allow, it wraps ErrIllegalTransition
+type TransitionError struct {
+	RequestId string
+	From, To  State
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("request %s: %v from %s to %s", e.RequestId, ErrIllegalTransition, e.From, e.To)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrIllegalTransition
+}
+
+// Transition is an entry in a request's history, the first is from Unknown to New when it is queued
+type Transition struct {
+	From, To State
+	At       time.Time
+}
+
 type Request struct {
 	RequestState State
 	Val          int
+	Result       any   // set by the handler when Finished
+	Err          error // set by the handler when Failed, or why it was Cancelled or TimedOut
+
+	deadline time.Time
+	ctx      context.Context
+	cancel   context.CancelCauseFunc
+	stop     func() bool // stops the context.AfterFunc that expires the request
+	history  []Transition
 }
 
 func NewRequest(val int) *Request {
@@ -45,35 +108,183 @@ func NewRequest(val int) *Request {
 	}
 }
 
+// RequestOption configures a request as it is queued
+type RequestOption func(*Request)
+
+// WithDeadline times the request out at t if it hasn't finished by then
+func WithDeadline(t time.Time) RequestOption {
+	return func(req *Request) {
+		req.deadline = t
+	}
+}
+
+// WithTimeout times the request out d after it is queued if it hasn't finished by then
+func WithTimeout(d time.Duration) RequestOption {
+	return func(req *Request) {
+		req.deadline = time.Now().Add(d)
+	}
+}
+
+// Handler runs a request, it should only read req.Val as the manager owns the other fields.
+// ctx is done when the request is cancelled or times out, the result is then discarded.
+type Handler func(ctx context.Context, req *Request) (any, error)
+
 type RequestManager struct {
 	requests map[string]*Request
 	mu       sync.RWMutex
+
+	handler Handler
+	workers int
+	pending []string   // IDs of queued requests in FIFO order, only used with a handler
+	ready   *sync.Cond // signalled on mu when pending grows or the manager closes
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+type RequestManagerOption func(*RequestManager)
+
+// WithHandler makes the manager run queued requests on a pool of workers. Without one
+// requests stay New until CompleteRequest is called, as before.
+func WithHandler(h Handler) RequestManagerOption {
+	return func(rm *RequestManager) {
+		rm.handler = h
+	}
+}
+
+// WithWorkers sets the pool size, it defaults to GOMAXPROCS and is at least 1
+func WithWorkers(n int) RequestManagerOption {
+	return func(rm *RequestManager) {
+		rm.workers = n
+	}
 }
 
-func NewRequestManager() *RequestManager {
-	return &RequestManager{
+func NewRequestManager(opts ...RequestManagerOption) *RequestManager {
+	rm := &RequestManager{
 		requests: make(map[string]*Request),
+		workers:  runtime.GOMAXPROCS(0),
+	}
+	for _, opt := range opts {
+		opt(rm)
 	}
+	rm.ready = sync.NewCond(&rm.mu)
+	if rm.workers < 1 {
+		rm.workers = 1
+	}
+
+	if rm.handler != nil {
+		rm.wg.Add(rm.workers)
+		for i := 0; i < rm.workers; i++ {
+			go rm.work()
+		}
+	}
+	return rm
 }
 
 /*
 Complete the below functions to enable concurrent processing of requests, querying their state and marking them as finished
 */
 
-func (rm *RequestManager) QueueRequest(req *Request) (requestId string) {
+// QueueRequest adds req to the manager, it is cancelled when ctx is and times out at any deadline in opts
+func (rm *RequestManager) QueueRequest(ctx context.Context, req *Request, opts ...RequestOption) (requestId string) {
+	for _, opt := range opts {
+		opt(req)
+	}
+	req.ctx, req.cancel = context.WithCancelCause(ctx)
+	if !req.deadline.IsZero() {
+		var cancel context.CancelFunc
+		req.ctx, cancel = context.WithDeadlineCause(req.ctx, req.deadline, ErrRequestTimedOut)
+		cancelCause := req.cancel
+		req.cancel = func(cause error) {
+			cancelCause(cause)
+			cancel()
+		}
+	}
+
 	requestId = newRequestId(10)
 	rm.mu.Lock()
 	defer rm.mu.Unlock()
 	rm.requests[requestId] = req
+	req.RequestState, req.history = StateNew, []Transition{{From: StateUnknown, To: StateNew, At: time.Now()}}
+	req.stop = context.AfterFunc(req.ctx, func() { rm.expire(requestId) })
+	if rm.handler != nil && !rm.closed {
+		rm.pending = append(rm.pending, requestId)
+		rm.ready.Signal()
+	}
 	return requestId
 }
 
-func (rm *RequestManager) CompleteRequest(requestId string) {
+// CompleteRequest finishes a Busy request, or a New one by way of Busy. Completing a request a worker
+// is running discards the handler's result.
+func (rm *RequestManager) CompleteRequest(requestId string) error {
 	rm.mu.Lock()
 	defer rm.mu.Unlock()
-	if req, exists := rm.requests[requestId]; exists {
-		req.RequestState = StateFinished
+	req, exists := rm.requests[requestId]
+	if !exists {
+		return fmt.Errorf("%w %s", ErrUnknownRequest, requestId)
+	}
+	if req.RequestState == StateNew {
+		if err := rm.transition(requestId, req, StateBusy, nil); err != nil {
+			return err
+		}
+	}
+	return rm.transition(requestId, req, StateFinished, nil)
+}
+
+// CancelRequest cancels a New or Busy request, a running handler sees its ctx done
+func (rm *RequestManager) CancelRequest(requestId string) error {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	req, exists := rm.requests[requestId]
+	if !exists {
+		return fmt.Errorf("%w %s", ErrUnknownRequest, requestId)
+	}
+	return rm.transition(requestId, req, StateCancelled, ErrRequestCancelled)
+}
+
+// RequestHistory returns every state the request has been in, oldest first
+func (rm *RequestManager) RequestHistory(requestId string) ([]Transition, error) {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	req, exists := rm.requests[requestId]
+	if !exists {
+		return nil, fmt.Errorf("%w %s", ErrUnknownRequest, requestId)
+	}
+	return append([]Transition(nil), req.history...), nil
+}
+
+// transition moves req to state to, recording it in the history, err becomes req.Err.
+// Reaching a final state releases the request's context, cancelling a running handler with err as the cause.
+// Callers hold rm.mu.
+func (rm *RequestManager) transition(requestId string, req *Request, to State, err error) error {
+	if !req.RequestState.CanTransitionTo(to) {
+		return &TransitionError{RequestId: requestId, From: req.RequestState, To: to}
+	}
+	req.history = append(req.history, Transition{From: req.RequestState, To: to, At: time.Now()})
+	req.RequestState = to
+	if to.Terminal() {
+		req.Err = err
+		if req.stop != nil {
+			req.stop()
+			req.cancel(err)
+		}
+	}
+	return nil
+}
+
+// expire runs when a request's context is done, because the caller's context was cancelled or the deadline passed
+func (rm *RequestManager) expire(requestId string) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.expired(requestId, rm.requests[requestId])
+}
+
+// expired moves a request whose context is done to Cancelled or TimedOut, unless it already reached a final state
+func (rm *RequestManager) expired(requestId string, req *Request) {
+	to := StateCancelled
+	if errors.Is(req.ctx.Err(), context.DeadlineExceeded) {
+		to = StateTimedOut
 	}
+	_ = rm.transition(requestId, req, to, context.Cause(req.ctx))
 }
 
 func (rm *RequestManager) QueryRequestState(requestId string) State {
@@ -85,18 +296,126 @@ func (rm *RequestManager) QueryRequestState(requestId string) State {
 	return StateUnknown
 }
 
+// QueryRequest returns a copy of the request, including its Result or Err once it has run
+func (rm *RequestManager) QueryRequest(requestId string) (Request, bool) {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	if req, exists := rm.requests[requestId]; exists {
+		return *req, true
+	}
+	return Request{}, false
+}
+
+// Close stops the workers once they have run every request already queued and waits for them,
+// requests queued after Close stay New
+func (rm *RequestManager) Close() {
+	rm.mu.Lock()
+	rm.closed = true
+	rm.ready.Broadcast()
+	rm.mu.Unlock()
+	rm.wg.Wait()
+}
+
+func (rm *RequestManager) work() {
+	defer rm.wg.Done()
+	for {
+		id, req, ok := rm.next()
+		if !ok {
+			return
+		}
+		result, err := rm.run(req)
+		rm.finish(id, result, err)
+	}
+}
+
+// next waits for a queued request that is still New and marks it Busy, ok is false once the manager is
+// closed and the queue is empty
+func (rm *RequestManager) next() (id string, req *Request, ok bool) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	for {
+		for len(rm.pending) == 0 && !rm.closed {
+			rm.ready.Wait()
+		}
+		if len(rm.pending) == 0 {
+			return "", nil, false
+		}
+		id, rm.pending = rm.pending[0], rm.pending[1:]
+		if req = rm.requests[id]; req.RequestState == StateNew && req.ctx.Err() == nil { // skip any completed or cancelled
+			_ = rm.transition(id, req, StateBusy, nil)
+			return id, req, true
+		}
+	}
+}
+
+// run calls the handler, turning a panic into an error so one bad request can't take down a worker
+func (rm *RequestManager) run(req *Request) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+	return rm.handler(req.ctx, req)
+}
+
+func (rm *RequestManager) finish(id string, result any, err error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	req := rm.requests[id]
+	switch {
+	case req.RequestState != StateBusy:
+		// completed, cancelled or timed out while running
+	case req.ctx.Err() != nil:
+		rm.expired(id, req) // the handler returned before expire got the lock
+	case err != nil:
+		_ = rm.transition(id, req, StateFailed, err)
+	default:
+		req.Result = result
+		_ = rm.transition(id, req, StateFinished, nil)
+	}
+}
+
 // "I recommend using a main method and really making your solution work hard" -->
 func main() {
 	rm := NewRequestManager()
 
 	// example
 	req := NewRequest(42)
-	reqID := rm.QueueRequest(req)
+	reqID := rm.QueueRequest(context.Background(), req)
 
 	// simulate
 	time.Sleep(1 * time.Second)
-	rm.CompleteRequest(reqID)
+	if err := rm.CompleteRequest(reqID); err != nil {
+		println("Complete request:", err.Error())
+	}
 
 	state := rm.QueryRequestState(reqID)
 	println("Request state:", state)
+
+	history, _ := rm.RequestHistory(reqID)
+	for _, tr := range history {
+		fmt.Printf("  %s %s -> %s\n", tr.At.Format(time.StampMilli), tr.From, tr.To)
+	}
+
+	// the same with a pool of workers running the requests
+	pool := NewRequestManager(WithWorkers(4), WithHandler(func(ctx context.Context, req *Request) (any, error) {
+		select {
+		case <-time.After(time.Duration(rand.Intn(100)) * time.Millisecond):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if req.Val%5 == 0 {
+			return nil, fmt.Errorf("%d is divisible by 5", req.Val)
+		}
+		return req.Val * req.Val, nil
+	}))
+	var ids []string
+	for i := 1; i <= 10; i++ {
+		ids = append(ids, pool.QueueRequest(context.Background(), NewRequest(i), WithTimeout(150*time.Millisecond)))
+	}
+	pool.Close()
+	for _, id := range ids {
+		req, _ := pool.QueryRequest(id)
+		fmt.Printf("Request %s: %s %v %v\n", id, req.RequestState, req.Result, req.Err)
+	}
 }
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"math/rand"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -20,7 +23,7 @@ func TestNewRequestManager(t *testing.T) { // test proper instantiation
 func TestQueueRequest(t *testing.T) { // test proper enqueue
 	rm := NewRequestManager()
 	req := NewRequest(42)
-	requestId := rm.QueueRequest(req)
+	requestId := rm.QueueRequest(context.Background(), req)
 	if requestId == "" {
 		t.Fatal("QueueRequest should return a non-empty request ID")
 	}
@@ -35,7 +38,7 @@ func TestQueueRequest(t *testing.T) { // test proper enqueue
 func TestCompleteRequest(t *testing.T) { // test proper finish state
 	rm := NewRequestManager()
 	req := NewRequest(42)
-	requestId := rm.QueueRequest(req)
+	requestId := rm.QueueRequest(context.Background(), req)
 	rm.CompleteRequest(requestId)
 	if rm.requests[requestId].RequestState != StateFinished {
 		t.Fatalf("Expected request state to be %s, got %s", StateFinished, rm.requests[requestId].RequestState)
@@ -45,7 +48,7 @@ func TestCompleteRequest(t *testing.T) { // test proper finish state
 func TestQueryRequestState(t *testing.T) { // ensure requestable
 	rm := NewRequestManager()
 	req := NewRequest(42)
-	requestId := rm.QueueRequest(req)
+	requestId := rm.QueueRequest(context.Background(), req)
 	state := rm.QueryRequestState(requestId)
 	if state != StateNew {
 		t.Fatalf("Expected request state to be %s, got %s", StateNew, state)
@@ -75,7 +78,7 @@ func TestConcurrentAccess(t *testing.T) { // safe concurrent access
 		go func(val int) {
 			defer wg.Done()
 			req := NewRequest(val)
-			reqID := rm.QueueRequest(req)
+			reqID := rm.QueueRequest(context.Background(), req)
 			time.Sleep(time.Millisecond * time.Duration(rand.Intn(100)))
 			rm.CompleteRequest(reqID)
 			state := rm.QueryRequestState(reqID)
@@ -91,7 +94,7 @@ func TestConcurrentAccess(t *testing.T) { // safe concurrent access
 func TestConcurrentQuery(t *testing.T) { // concurrent query
 	rm := NewRequestManager()
 	req := NewRequest(42)
-	reqID := rm.QueueRequest(req)
+	reqID := rm.QueueRequest(context.Background(), req)
 
 	var wg sync.WaitGroup
 	const numRoutines = 100
@@ -118,7 +121,7 @@ func TestConcurrentMethodAccess(t *testing.T) { // concurrent method access
 
 	for i := 0; i < numRequests; i++ {
 		req := NewRequest(i)
-		reqID := rm.QueueRequest(req)
+		reqID := rm.QueueRequest(context.Background(), req)
 		for j := 0; j < numRoutines; j++ {
 			wg.Add(1)
 			go func(id string) {
@@ -136,10 +139,281 @@ func TestConcurrentMethodAccess(t *testing.T) { // concurrent method access
 			go func() {
 				defer wg.Done()
 				req := NewRequest(rand.Intn(100))
-				rm.QueueRequest(req)
+				rm.QueueRequest(context.Background(), req)
 			}()
 		}
 	}
 
 	wg.Wait()
 }
+
+func TestWorkerPoolRunsRequests(t *testing.T) { // handler results and errors are recorded
+	errOdd := errors.New("odd")
+	rm := NewRequestManager(WithWorkers(4), WithHandler(func(ctx context.Context, req *Request) (any, error) {
+		if req.Val%2 == 1 {
+			return nil, errOdd
+		}
+		return req.Val * 10, nil
+	}))
+
+	ids := make([]string, 100)
+	for i := range ids {
+		ids[i] = rm.QueueRequest(context.Background(), NewRequest(i))
+	}
+	rm.Close()
+
+	for i, id := range ids {
+		req, ok := rm.QueryRequest(id)
+		if !ok {
+			t.Fatalf("request %s not found", id)
+		}
+		if i%2 == 1 {
+			if req.RequestState != StateFailed || !errors.Is(req.Err, errOdd) {
+				t.Fatalf("Expected request %d to be %s with %v, got %s %v", i, StateFailed, errOdd, req.RequestState, req.Err)
+			}
+			continue
+		}
+		if req.RequestState != StateFinished || req.Result != i*10 {
+			t.Fatalf("Expected request %d to be %s with %d, got %s %v", i, StateFinished, i*10, req.RequestState, req.Result)
+		}
+	}
+}
+
+func TestWorkerPoolSize(t *testing.T) { // never more handlers running than workers, and requests are Busy while they run
+	const workers = 3
+	var running, maxRunning atomic.Int32
+	release := make(chan struct{})
+	rm := NewRequestManager(WithWorkers(workers), WithHandler(func(ctx context.Context, req *Request) (any, error) {
+		n := running.Add(1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		<-release
+		running.Add(-1)
+		return nil, nil
+	}))
+
+	ids := make([]string, 10)
+	for i := range ids {
+		ids[i] = rm.QueueRequest(context.Background(), NewRequest(i))
+	}
+	for running.Load() < workers {
+		time.Sleep(time.Millisecond)
+	}
+	busy := 0
+	for _, id := range ids {
+		if rm.QueryRequestState(id) == StateBusy {
+			busy++
+		}
+	}
+	if busy != workers {
+		t.Fatalf("Expected %d requests to be %s, got %d", workers, StateBusy, busy)
+	}
+
+	close(release)
+	rm.Close()
+	if maxRunning.Load() != workers {
+		t.Fatalf("Expected at most %d handlers at once, got %d", workers, maxRunning.Load())
+	}
+	for _, id := range ids {
+		if state := rm.QueryRequestState(id); state != StateFinished {
+			t.Fatalf("Expected request state to be %s, got %s", StateFinished, state)
+		}
+	}
+}
+
+func TestWorkerPoolHandlerPanic(t *testing.T) { // a panicking handler fails its request, not the worker
+	rm := NewRequestManager(WithWorkers(1), WithHandler(func(ctx context.Context, req *Request) (any, error) {
+		if req.Val == 0 {
+			panic("boom")
+		}
+		return req.Val, nil
+	}))
+	bad := rm.QueueRequest(context.Background(), NewRequest(0))
+	good := rm.QueueRequest(context.Background(), NewRequest(1))
+	rm.Close()
+
+	if req, _ := rm.QueryRequest(bad); req.RequestState != StateFailed || req.Err == nil {
+		t.Fatalf("Expected request state to be %s with an error, got %s %v", StateFailed, req.RequestState, req.Err)
+	}
+	if state := rm.QueryRequestState(good); state != StateFinished {
+		t.Fatalf("Expected request state to be %s, got %s", StateFinished, state)
+	}
+}
+
+// waitForState polls until the request reaches want, expiry happens on another goroutine
+func waitForState(t *testing.T, rm *RequestManager, id string, want State) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		state := rm.QueryRequestState(id)
+		if state == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected request state to be %s, got %s", want, state)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCancelRequest(t *testing.T) { // cancel a queued request, not a finished one
+	rm := NewRequestManager()
+	id := rm.QueueRequest(context.Background(), NewRequest(42))
+	if err := rm.CancelRequest(id); err != nil {
+		t.Fatalf("CancelRequest should cancel a New request, got %v", err)
+	}
+	if req, _ := rm.QueryRequest(id); req.RequestState != StateCancelled || !errors.Is(req.Err, ErrRequestCancelled) {
+		t.Fatalf("Expected request state to be %s, got %s %v", StateCancelled, req.RequestState, req.Err)
+	}
+	if err := rm.CancelRequest(id); !errors.Is(err, ErrIllegalTransition) {
+		t.Fatalf("CancelRequest should not cancel a request twice, got %v", err)
+	}
+
+	finished := rm.QueueRequest(context.Background(), NewRequest(1))
+	rm.CompleteRequest(finished)
+	if err := rm.CancelRequest(finished); !errors.Is(err, ErrIllegalTransition) {
+		t.Fatalf("CancelRequest should only cancel New or Busy requests, got %v", err)
+	}
+	if err := rm.CancelRequest("nonexistent"); !errors.Is(err, ErrUnknownRequest) {
+		t.Fatalf("Expected %v, got %v", ErrUnknownRequest, err)
+	}
+	if state := rm.QueryRequestState(finished); state != StateFinished {
+		t.Fatalf("Expected request state to be %s, got %s", StateFinished, state)
+	}
+}
+
+func TestCancelRunningRequest(t *testing.T) { // the handler sees ctx.Done() and its result is dropped
+	started := make(chan struct{})
+	rm := NewRequestManager(WithWorkers(1), WithHandler(func(ctx context.Context, req *Request) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return "too late", nil
+	}))
+	id := rm.QueueRequest(context.Background(), NewRequest(42))
+	<-started
+	if err := rm.CancelRequest(id); err != nil {
+		t.Fatalf("CancelRequest should cancel a Busy request, got %v", err)
+	}
+	rm.Close()
+
+	if req, _ := rm.QueryRequest(id); req.RequestState != StateCancelled || req.Result != nil {
+		t.Fatalf("Expected request state to be %s without a result, got %s %v", StateCancelled, req.RequestState, req.Result)
+	}
+}
+
+func TestQueueRequestContextCancelled(t *testing.T) { // cancelling the caller's context cancels the request
+	rm := NewRequestManager()
+	ctx, cancel := context.WithCancel(context.Background())
+	id := rm.QueueRequest(ctx, NewRequest(42))
+	cancel()
+	waitForState(t, rm, id, StateCancelled)
+	if req, _ := rm.QueryRequest(id); !errors.Is(req.Err, context.Canceled) {
+		t.Fatalf("Expected %v, got %v", context.Canceled, req.Err)
+	}
+}
+
+func TestRequestTimeout(t *testing.T) { // queued and running requests time out
+	block := make(chan struct{})
+	defer close(block)
+	rm := NewRequestManager(WithWorkers(1), WithHandler(func(ctx context.Context, req *Request) (any, error) {
+		select {
+		case <-block:
+		case <-ctx.Done():
+		}
+		return nil, ctx.Err()
+	}))
+
+	running := rm.QueueRequest(context.Background(), NewRequest(1), WithTimeout(20*time.Millisecond))
+	queued := rm.QueueRequest(context.Background(), NewRequest(2), WithDeadline(time.Now().Add(10*time.Millisecond)))
+	later := rm.QueueRequest(context.Background(), NewRequest(3), WithTimeout(time.Hour))
+
+	waitForState(t, rm, running, StateTimedOut)
+	waitForState(t, rm, queued, StateTimedOut)
+	if req, _ := rm.QueryRequest(queued); !errors.Is(req.Err, ErrRequestTimedOut) {
+		t.Fatalf("Expected %v, got %v", ErrRequestTimedOut, req.Err)
+	}
+	waitForState(t, rm, later, StateBusy)
+	_ = rm.CancelRequest(later)
+}
+
+func TestStateMachine(t *testing.T) { // only the transitions New -> Busy -> Finished/Failed/Cancelled/TimedOut are allowed
+	legal := map[[2]State]bool{
+		{StateNew, StateBusy}: true, {StateNew, StateCancelled}: true, {StateNew, StateTimedOut}: true,
+		{StateBusy, StateFinished}: true, {StateBusy, StateFailed}: true, {StateBusy, StateCancelled}: true, {StateBusy, StateTimedOut}: true,
+	}
+	states := []State{StateUnknown, StateNew, StateBusy, StateFinished, StateFailed, StateCancelled, StateTimedOut}
+	for _, from := range states {
+		for _, to := range states {
+			if got := from.CanTransitionTo(to); got != legal[[2]State{from, to}] {
+				t.Errorf("%s.CanTransitionTo(%s) = %v", from, to, got)
+			}
+		}
+	}
+	for _, state := range states {
+		terminal := state == StateFinished || state == StateFailed || state == StateCancelled || state == StateTimedOut
+		if state.Terminal() != terminal {
+			t.Errorf("%s.Terminal() = %v", state, state.Terminal())
+		}
+	}
+}
+
+func TestCompleteRequestTwice(t *testing.T) { // finishing again is an illegal transition
+	rm := NewRequestManager()
+	id := rm.QueueRequest(context.Background(), NewRequest(42))
+	if err := rm.CompleteRequest(id); err != nil {
+		t.Fatal(err)
+	}
+
+	err := rm.CompleteRequest(id)
+	var transitionErr *TransitionError
+	if !errors.As(err, &transitionErr) || !errors.Is(err, ErrIllegalTransition) {
+		t.Fatalf("Expected a *TransitionError, got %v", err)
+	}
+	if transitionErr.RequestId != id || transitionErr.From != StateFinished || transitionErr.To != StateFinished {
+		t.Fatalf("Unexpected %+v", transitionErr)
+	}
+	if err := rm.CompleteRequest("nonexistent"); !errors.Is(err, ErrUnknownRequest) {
+		t.Fatalf("Expected %v, got %v", ErrUnknownRequest, err)
+	}
+}
+
+func TestRequestHistory(t *testing.T) { // every transition is recorded in order with a time
+	rm := NewRequestManager(WithWorkers(1), WithHandler(func(ctx context.Context, req *Request) (any, error) {
+		return nil, errors.New("failed")
+	}))
+	before := time.Now()
+	failed := rm.QueueRequest(context.Background(), NewRequest(1))
+	rm.Close()
+
+	history, err := rm.RequestHistory(failed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Transition{{From: StateUnknown, To: StateNew}, {From: StateNew, To: StateBusy}, {From: StateBusy, To: StateFailed}}
+	if len(history) != len(want) {
+		t.Fatalf("Expected %d transitions, got %+v", len(want), history)
+	}
+	for i, tr := range history {
+		if tr.From != want[i].From || tr.To != want[i].To {
+			t.Fatalf("Expected transition %d to be %s -> %s, got %s -> %s", i, want[i].From, want[i].To, tr.From, tr.To)
+		}
+		if tr.At.Before(before) || (i > 0 && tr.At.Before(history[i-1].At)) {
+			t.Fatalf("Transition %d at %v is out of order", i, tr.At)
+		}
+	}
+
+	rm = NewRequestManager()
+	completed := rm.QueueRequest(context.Background(), NewRequest(2))
+	_ = rm.CompleteRequest(completed)
+	_ = rm.CancelRequest(completed) // rejected, so not recorded
+	if history, _ := rm.RequestHistory(completed); len(history) != 3 || history[1].To != StateBusy || history[2].To != StateFinished {
+		t.Fatalf("Expected New -> Busy -> Finished, got %+v", history)
+	}
+	if _, err := rm.RequestHistory("nonexistent"); !errors.Is(err, ErrUnknownRequest) {
+		t.Fatalf("Expected %v, got %v", ErrUnknownRequest, err)
+	}
+}
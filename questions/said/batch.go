@@ -0,0 +1,209 @@
+package said
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type BatchFormat int
+
+const (
+	FormatLines BatchFormat = iota // one ID per line, blank lines are skipped
+	FormatCSV
+)
+
+// BatchValidator validates a stream of IDs, e.g. an onboarding file, writing one CSV result row per input row
+type BatchValidator struct {
+	Parser  *SAIDParser // defaults to NewSAIDParser()
+	Format  BatchFormat
+	Column  int  // CSV column (0 based) that holds the ID
+	Header  bool // CSV input starts with a header row, it is skipped
+	Workers int  // defaults to runtime.NumCPU()
+}
+
+// BatchSummary is the report for a whole batch
+type BatchSummary struct {
+	Rows    int
+	Valid   int
+	Invalid int
+	Errors  map[string]int // invalid rows per error code
+}
+
+var batchHeader = []string{"row", "id", "valid", "error", "gender", "date_of_birth", "citizenship"}
+
+type batchRow struct {
+	n       int
+	id      string
+	missing bool // CSV row without the ID column
+	details *SAIDDetails
+	err     error
+	done    chan struct{}
+}
+
+// Validate reads IDs from r and writes results to w in input order while parsing them concurrently.
+// The returned error is for reading or writing, invalid IDs are only reported in the results and summary.
+func (bv *BatchValidator) Validate(r io.Reader, w io.Writer) (*BatchSummary, error) {
+	parser := bv.Parser
+	if parser == nil {
+		parser = NewSAIDParser()
+	}
+	workers := bv.Workers
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+
+	jobs := make(chan *batchRow)
+	ordered := make(chan *batchRow, workers*4) // bounds the rows in flight
+	stop := make(chan struct{})
+	var readErr error
+
+	go func() {
+		defer close(ordered)
+		defer close(jobs)
+		readErr = bv.readRows(r, func(row *batchRow) bool {
+			select {
+			case ordered <- row:
+			case <-stop:
+				return false
+			}
+			jobs <- row
+			return true
+		})
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for row := range jobs {
+				if !row.missing {
+					row.details, row.err = parser.Parse(row.id)
+				}
+				close(row.done)
+			}
+		}()
+	}
+	defer wg.Wait()
+
+	summary := &BatchSummary{Errors: make(map[string]int)}
+	cw := csv.NewWriter(w)
+	writeErr := cw.Write(batchHeader)
+	if writeErr != nil {
+		close(stop)
+	}
+	for row := range ordered {
+		<-row.done
+		if writeErr != nil {
+			continue // drain so the reader can exit
+		}
+
+		summary.Rows++
+		record := []string{strconv.Itoa(row.n), row.id, "false", "", "", "", ""}
+		switch {
+		case row.missing:
+			summary.Invalid++
+			summary.Errors["missing_column"]++
+			record[3] = "missing_column"
+		case row.err != nil:
+			summary.Invalid++
+			summary.Errors[ErrorCode(row.err)]++
+			record[3] = ErrorCode(row.err)
+		default:
+			summary.Valid++
+			record[2] = "true"
+			record[4] = string(row.details.Gender)
+			record[5] = row.details.DateOfBirth.Format("2006-01-02")
+			record[6] = row.details.Citizenship.String()
+		}
+
+		if writeErr = cw.Write(record); writeErr != nil {
+			close(stop)
+		}
+	}
+
+	if writeErr == nil {
+		cw.Flush()
+		writeErr = cw.Error()
+	}
+	if writeErr != nil {
+		return summary, writeErr
+	}
+	return summary, readErr
+}
+
+// readRows calls emit for every row until the input ends or emit returns false
+func (bv *BatchValidator) readRows(r io.Reader, emit func(*batchRow) bool) error {
+	n := 0
+	newRow := func(id string) *batchRow {
+		n++
+		return &batchRow{n: n, id: id, done: make(chan struct{})}
+	}
+
+	if bv.Format == FormatLines {
+		sc := bufio.NewScanner(r)
+		for sc.Scan() {
+			id := strings.TrimSpace(sc.Text())
+			if id == "" {
+				continue
+			}
+			if !emit(newRow(id)) {
+				return nil
+			}
+		}
+		return sc.Err()
+	}
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	if bv.Header {
+		if _, err := cr.Read(); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var row *batchRow
+		if bv.Column < len(record) {
+			row = newRow(strings.TrimSpace(record[bv.Column]))
+		} else {
+			row = newRow("")
+			row.missing = true
+		}
+		if !emit(row) {
+			return nil
+		}
+	}
+}
+
+func (s *BatchSummary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "rows: %d\nvalid: %d\ninvalid: %d\n", s.Rows, s.Valid, s.Invalid)
+
+	codes := make([]string, 0, len(s.Errors))
+	for code := range s.Errors {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		fmt.Fprintf(&b, "  %s: %d\n", code, s.Errors[code])
+	}
+	return b.String()
+}
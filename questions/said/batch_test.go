@@ -0,0 +1,93 @@
+package said
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchValidatorLines(t *testing.T) {
+	in := "9103105017084\n\n  9604185215084 \r\n910310501708\n9101015017089\n"
+	var out bytes.Buffer
+
+	bv := &BatchValidator{Parser: testParser, Workers: 2}
+	summary, err := bv.Validate(strings.NewReader(in), &out)
+	require.NoError(t, err)
+
+	assert.Equal(t, "row,id,valid,error,gender,date_of_birth,citizenship\n"+
+		"1,9103105017084,true,,M,1991-03-10,citizen\n"+
+		"2,9604185215084,true,,M,1996-04-18,citizen\n"+
+		"3,910310501708,false,length,,,\n"+
+		"4,9101015017089,false,checksum,,,\n", out.String())
+
+	assert.Equal(t, 4, summary.Rows)
+	assert.Equal(t, 2, summary.Valid)
+	assert.Equal(t, 2, summary.Invalid)
+	assert.Equal(t, map[string]int{"length": 1, "checksum": 1}, summary.Errors)
+	assert.Equal(t, "rows: 4\nvalid: 2\ninvalid: 2\n  checksum: 1\n  length: 1\n", summary.String())
+}
+
+func TestBatchValidatorCSV(t *testing.T) {
+	in := "name,id\nbrad,9604185215084\nnobody\n\"x, y\",91031050x7084\n"
+	var out bytes.Buffer
+
+	bv := &BatchValidator{Parser: testParser, Format: FormatCSV, Column: 1, Header: true}
+	summary, err := bv.Validate(strings.NewReader(in), &out)
+	require.NoError(t, err)
+
+	assert.Equal(t, "row,id,valid,error,gender,date_of_birth,citizenship\n"+
+		"1,9604185215084,true,,M,1996-04-18,citizen\n"+
+		"2,,false,missing_column,,,\n"+
+		"3,91031050x7084,false,non_numeric,,,\n", out.String())
+	assert.Equal(t, 3, summary.Rows)
+	assert.Equal(t, 1, summary.Valid)
+}
+
+func TestBatchValidatorKeepsOrder(t *testing.T) {
+	now := time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+	gen := NewSAIDGenerator(7, now)
+
+	var in strings.Builder
+	var ids []string
+	for i := 0; i < 5000; i++ {
+		id, _ := gen.Next()
+		if i%10 == 0 {
+			id = id[:12] // every tenth is too short
+		}
+		ids = append(ids, id)
+		in.WriteString(id + "\n")
+	}
+
+	var out bytes.Buffer
+	bv := &BatchValidator{Parser: testParser, Workers: 8}
+	summary, err := bv.Validate(strings.NewReader(in.String()), &out)
+	require.NoError(t, err)
+	assert.Equal(t, 5000, summary.Rows)
+	assert.Equal(t, 500, summary.Invalid)
+
+	records, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, len(ids)+1)
+	for i, id := range ids {
+		assert.Equal(t, strconv.Itoa(i+1), records[i+1][0])
+		assert.Equal(t, id, records[i+1][1])
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestBatchValidatorWriteError(t *testing.T) {
+	in := strings.Repeat("9103105017084\n", 10000)
+	bv := &BatchValidator{Parser: testParser}
+	_, err := bv.Validate(strings.NewReader(in), failingWriter{})
+	assert.EqualError(t, err, "disk full")
+}
@@ -0,0 +1,196 @@
+package said
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Encoding and decoding always validate by parsing with ParseSAIDNumber (i.e. the wall clock),
+// so Age is as at the time of decoding and an invalid or zero ID can't be written out. Encoding
+// SAIDDetails doesn't enforce citizenship, as they may come from a lenient parser.
+
+var ErrNullID = errors.New("NULL is not an ID number")
+
+// SAID is a raw ID number for use as a struct field in request bodies and DB rows, it is
+// validated whenever it is decoded or scanned and only parsed into SAIDDetails on demand
+type SAID string
+
+// Details parses the ID
+func (s SAID) Details() (*SAIDDetails, error) {
+	return ParseSAIDNumber(string(s))
+}
+
+func (s SAID) Valid() bool {
+	_, err := s.Details()
+	return err == nil
+}
+
+func (s SAID) MarshalText() ([]byte, error) {
+	if _, err := s.Details(); err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+func (s *SAID) UnmarshalText(text []byte) error {
+	if _, err := ParseSAIDNumber(string(text)); err != nil {
+		return err
+	}
+	*s = SAID(text)
+	return nil
+}
+
+// UnmarshalJSON rejects null, which encoding/json would otherwise turn into "" without validating
+func (s *SAID) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return ErrNullID
+	}
+	var id string
+	if err := json.Unmarshal(data, &id); err != nil {
+		return err
+	}
+	return s.UnmarshalText([]byte(id))
+}
+
+func (s *SAID) Scan(src interface{}) error {
+	text, err := scanText(src)
+	if err != nil {
+		return err
+	}
+	return s.UnmarshalText(text)
+}
+
+func (s SAID) Value() (driver.Value, error) {
+	if _, err := s.Details(); err != nil {
+		return nil, err
+	}
+	return string(s), nil
+}
+
+// scanText accepts the column types an ID number can be stored as
+func scanText(src interface{}) ([]byte, error) {
+	switch v := src.(type) {
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	case nil:
+		return nil, ErrNullID
+	}
+	return nil, fmt.Errorf("cannot scan %T into an ID number", src)
+}
+
+// detailsJSON is the JSON form of SAIDDetails, only ID is read when decoding
+type detailsJSON struct {
+	ID          string            `json:"id"`
+	Gender      Gender            `json:"gender"`
+	DateOfBirth string            `json:"dateOfBirth"`
+	Age         int               `json:"age"`
+	Citizenship CitizenshipStatus `json:"citizenship"`
+}
+
+// MarshalJSON encodes the details as an object, e.g.
+// {"id":"9103105017084","gender":"M","dateOfBirth":"1991-03-10","age":30,"citizenship":"citizen"}
+func (d SAIDDetails) MarshalJSON() ([]byte, error) {
+	id, err := d.validNumber()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(detailsJSON{
+		ID:          id,
+		Gender:      d.Gender,
+		DateOfBirth: d.DateOfBirth.Format("2006-01-02"),
+		Age:         d.Age,
+		Citizenship: d.Citizenship,
+	})
+}
+
+// UnmarshalJSON accepts either the object from MarshalJSON or a plain ID number string,
+// everything other than the ID is derived by parsing it again
+func (d *SAIDDetails) UnmarshalJSON(data []byte) error {
+	var id string
+	if err := json.Unmarshal(data, &id); err != nil {
+		var obj detailsJSON
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		id = obj.ID
+	}
+	return d.UnmarshalText([]byte(id))
+}
+
+func (d SAIDDetails) MarshalText() ([]byte, error) {
+	id, err := d.validNumber()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(id), nil
+}
+
+func (d *SAIDDetails) UnmarshalText(text []byte) error {
+	details, err := ParseSAIDNumber(string(text))
+	if err != nil {
+		return err
+	}
+	*d = *details
+	return nil
+}
+
+func (d *SAIDDetails) Scan(src interface{}) error {
+	text, err := scanText(src)
+	if err != nil {
+		return err
+	}
+	return d.UnmarshalText(text)
+}
+
+func (d SAIDDetails) Value() (driver.Value, error) {
+	id, err := d.validNumber()
+	if err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+// encodingParser checks details before they are encoded, it doesn't enforce citizenship so that
+// details from a WithStrictCitizenship(false) parser can be written out
+var encodingParser = NewSAIDParser(WithStrictCitizenship(false))
+
+// validNumber returns the ID if it parses, the *ValidationError if not
+func (d SAIDDetails) validNumber() (string, error) {
+	id := d.Number()
+	var parsed SAIDDetails
+	if err := encodingParser.ParseInto(id, &parsed); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (g Gender) MarshalText() ([]byte, error) {
+	return []byte(g), nil
+}
+
+func (g *Gender) UnmarshalText(text []byte) error {
+	switch Gender(text) {
+	case Male, Female:
+		*g = Gender(text)
+		return nil
+	}
+	return fmt.Errorf("unknown gender %q", text)
+}
+
+func (c CitizenshipStatus) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+func (c *CitizenshipStatus) UnmarshalText(text []byte) error {
+	for _, status := range []CitizenshipStatus{Citizen, PermanentResident, Refugee, CitizenshipInvalid} {
+		if status.String() == string(text) {
+			*c = status
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown citizenship status %q", text)
+}
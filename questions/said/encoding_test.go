@@ -0,0 +1,151 @@
+package said
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type signupRequest struct {
+	Name string `json:"name"`
+	ID   SAID   `json:"id"`
+}
+
+func TestSAIDJSON(t *testing.T) {
+	var req signupRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"name":"brad","id":"9604185215084"}`), &req))
+	assert.Equal(t, SAID("9604185215084"), req.ID)
+
+	details, err := req.ID.Details()
+	require.NoError(t, err)
+	assert.Equal(t, Male, details.Gender)
+
+	out, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"brad","id":"9604185215084"}`, string(out))
+
+	err = json.Unmarshal([]byte(`{"name":"brad","id":"9604185215083"}`), &req)
+	assert.ErrorIs(t, err, ErrChecksum)
+
+	err = json.Unmarshal([]byte(`{"name":"brad","id":null}`), &req)
+	assert.ErrorIs(t, err, ErrNullID)
+	assert.Error(t, json.Unmarshal([]byte(`{"name":"brad","id":42}`), &req))
+
+	// invalid IDs don't go out either
+	_, err = json.Marshal(signupRequest{Name: "brad", ID: "garbage"})
+	assert.ErrorIs(t, err, ErrLength)
+	_, err = json.Marshal(signupRequest{Name: "brad"})
+	assert.ErrorIs(t, err, ErrLength)
+}
+
+func TestSAIDSQL(t *testing.T) {
+	var id SAID
+	require.NoError(t, id.Scan([]byte("9103105017084")))
+	assert.Equal(t, SAID("9103105017084"), id)
+
+	v, err := id.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "9103105017084", v)
+
+	assert.ErrorIs(t, id.Scan("910310501708"), ErrLength)
+	assert.ErrorIs(t, id.Scan(nil), ErrNullID)
+	assert.Error(t, id.Scan(int64(9103105017084)))
+
+	_, err = SAID("nope").Value()
+	assert.ErrorIs(t, err, ErrLength)
+	assert.False(t, SAID("nope").Valid())
+}
+
+func TestSAIDDetailsJSON(t *testing.T) {
+	details, err := testParser.Parse("9103105017084")
+	require.NoError(t, err)
+
+	out, err := json.Marshal(details)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"9103105017084","gender":"M","dateOfBirth":"1991-03-10","age":30,"citizenship":"citizen"}`, string(out))
+
+	// as an object or a plain string
+	for _, in := range []string{string(out), `"9103105017084"`} {
+		var decoded SAIDDetails
+		require.NoError(t, json.Unmarshal([]byte(in), &decoded), in)
+		assert.Equal(t, details.DateOfBirth, decoded.DateOfBirth)
+		assert.Equal(t, "9103105017084", decoded.Number())
+	}
+
+	var decoded SAIDDetails
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"id":"9103105017083"}`), &decoded), ErrChecksum)
+	assert.Error(t, json.Unmarshal([]byte(`42`), &decoded))
+}
+
+func TestSAIDDetailsTextAndSQL(t *testing.T) {
+	var details SAIDDetails
+	require.NoError(t, details.UnmarshalText([]byte("9103105017084")))
+	text, err := details.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "9103105017084", string(text))
+
+	require.NoError(t, details.Scan("9604185215084"))
+	v, err := details.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "9604185215084", v)
+	assert.Error(t, details.Scan(nil))
+}
+
+func TestLenientSAIDDetailsEncode(t *testing.T) {
+	lenient := NewSAIDParser(WithClock(testParser.now), WithStrictCitizenship(false))
+	details, err := lenient.Parse("9103105017381")
+	require.NoError(t, err)
+	require.Equal(t, CitizenshipInvalid, details.Citizenship)
+
+	out, err := json.Marshal(details)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `"id":"9103105017381"`)
+
+	text, err := details.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "9103105017381", string(text))
+
+	v, err := details.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "9103105017381", v)
+}
+
+func TestZeroSAIDDetailsDoesNotEncode(t *testing.T) {
+	var zero SAIDDetails
+	var validationErr *ValidationError
+
+	_, err := zero.Value()
+	assert.ErrorIs(t, err, ErrLength)
+	assert.ErrorAs(t, err, &validationErr)
+
+	_, err = zero.MarshalText()
+	assert.ErrorIs(t, err, ErrLength)
+
+	_, err = json.Marshal(zero)
+	assert.ErrorIs(t, err, ErrLength)
+
+	// a hand-edited segment fails the checksum
+	details, err := testParser.Parse("9103105017084")
+	require.NoError(t, err)
+	details.ChecksumDigit = 3
+	_, err = details.Value()
+	assert.ErrorIs(t, err, ErrChecksum)
+}
+
+func TestGenderAndCitizenshipText(t *testing.T) {
+	var g Gender
+	require.NoError(t, json.Unmarshal([]byte(`"F"`), &g))
+	assert.Equal(t, Female, g)
+	assert.Error(t, json.Unmarshal([]byte(`"X"`), &g))
+
+	var c CitizenshipStatus
+	require.NoError(t, json.Unmarshal([]byte(`"refugee"`), &c))
+	assert.Equal(t, Refugee, c)
+	assert.Error(t, json.Unmarshal([]byte(`"alien"`), &c))
+
+	out, err := json.Marshal(PermanentResident)
+	require.NoError(t, err)
+	assert.Equal(t, `"permanent_resident"`, string(out))
+}
@@ -0,0 +1,115 @@
+package said
+
+import (
+	"errors"
+	"testing"
+	"testing/quick"
+	"time"
+	"unicode/utf8"
+
+	"greystonetec.co.za/tests/go-candidate-test/luhn"
+)
+
+// Seed corpora are in testdata/fuzz, run with e.g. go test -fuzz=FuzzParseSAIDNumber ./said
+
+var fuzzNow = time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+func FuzzParseSAIDNumber(f *testing.F) {
+	strict := NewSAIDParser(WithClock(func() time.Time { return fuzzNow }))
+	lenient := NewSAIDParser(WithClock(func() time.Time { return fuzzNow }), WithNormalisation(), WithStrictCitizenship(false))
+
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, parser := range []*SAIDParser{strict, lenient} {
+			details, err := parser.Parse(s)
+			if err != nil {
+				if details != nil {
+					t.Fatalf("Parse(%q) returned details with error %v", s, err)
+				}
+				var validationErr *ValidationError
+				if !errors.As(err, &validationErr) {
+					t.Fatalf("Parse(%q) returned %T, want *ValidationError", s, err)
+				}
+				if validationErr.Pos < 0 || validationErr.Pos > 13 {
+					t.Fatalf("Parse(%q) error position %d out of range", s, validationErr.Pos)
+				}
+				continue
+			}
+
+			if !luhn.Validate(details.Number()) {
+				t.Fatalf("Parse(%q) accepted %s which fails the checksum", s, details.Number())
+			}
+			if parser == strict && details.Number() != s {
+				t.Fatalf("Parse(%q) = %s", s, details.Number())
+			}
+			if details.Age < 0 || details.Age > 100 {
+				t.Fatalf("Parse(%q) age %d outside the 100 year window", s, details.Age)
+			}
+		}
+
+		// normalising never grows the input or changes what is already normal
+		normalised, edits := NormaliseSAIDNumber(s)
+		if len(normalised) > len(s) {
+			t.Fatalf("NormaliseSAIDNumber(%q) = %q is longer", s, normalised)
+		}
+		if again, more := NormaliseSAIDNumber(normalised); again != normalised || more != nil {
+			t.Fatalf("NormaliseSAIDNumber(%q) is not idempotent: %q then %q", s, normalised, again)
+		}
+		if edits == nil && normalised != s {
+			t.Fatalf("NormaliseSAIDNumber(%q) = %q without edits", s, normalised)
+		}
+		if utf8.ValidString(s) && !utf8.ValidString(normalised) {
+			t.Fatalf("NormaliseSAIDNumber(%q) = %q is not valid UTF-8", s, normalised)
+		}
+	})
+}
+
+// FuzzSingleDigitMutation changes one digit of a generated ID, Luhn catches every single digit error
+func FuzzSingleDigitMutation(f *testing.F) {
+	parser := NewSAIDParser(WithClock(func() time.Time { return fuzzNow }))
+
+	f.Fuzz(func(t *testing.T, seed int64, pos, delta uint8) {
+		id, _ := NewSAIDGenerator(seed, fuzzNow).Next()
+
+		b := []byte(id)
+		i := int(pos) % len(b)
+		b[i] = '0' + (b[i]-'0'+delta%9+1)%10
+		mutated := string(b)
+
+		if luhn.Validate(mutated) {
+			t.Fatalf("%s -> %s passes the checksum", id, mutated)
+		}
+		if _, err := parser.Parse(mutated); err == nil {
+			t.Fatalf("%s -> %s parsed", id, mutated)
+		}
+	})
+}
+
+func TestGeneratedIDsRoundTrip(t *testing.T) {
+	parser := NewSAIDParser(WithClock(func() time.Time { return fuzzNow }))
+
+	property := func(seed int64) bool {
+		id, spec := NewSAIDGenerator(seed, fuzzNow).Next()
+		details, err := parser.Parse(id)
+		return err == nil &&
+			details.Number() == id &&
+			details.DateOfBirth.Equal(spec.DateOfBirth) &&
+			details.Gender == spec.Gender &&
+			details.Citizenship == spec.Citizenship &&
+			details.Sequence == spec.Sequence
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseNeverPanics(t *testing.T) {
+	parser := NewSAIDParser(WithNormalisation(), WithStrictCitizenship(false))
+
+	property := func(b []byte) bool {
+		details, err := parser.Parse(string(b))
+		return (details == nil) != (err == nil)
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 5000}); err != nil {
+		t.Fatal(err)
+	}
+}
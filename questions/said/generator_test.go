@@ -0,0 +1,88 @@
+package said
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSAIDNumberInstructionExample(t *testing.T) {
+	id, err := GenerateSAIDNumber(SAIDSpec{
+		DateOfBirth: time.Date(1991, time.March, 10, 0, 0, 0, 0, time.UTC),
+		Gender:      Male,
+		Citizenship: Citizen,
+		Sequence:    17,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "9103105017084", id)
+}
+
+func TestGenerateSAIDNumberRejectsBadSpecs(t *testing.T) {
+	dob := time.Date(1991, time.March, 10, 0, 0, 0, 0, time.UTC)
+	for _, spec := range []SAIDSpec{
+		{Gender: Male, Citizenship: Citizen},
+		{DateOfBirth: dob, Gender: "X", Citizenship: Citizen},
+		{DateOfBirth: dob, Gender: Female, Citizenship: Citizen, Sequence: -1},
+		{DateOfBirth: dob, Gender: Female, Citizenship: Citizen, Sequence: 5000},
+		{DateOfBirth: dob, Gender: Female},
+	} {
+		_, err := GenerateSAIDNumber(spec)
+		assert.Error(t, err, "%+v", spec)
+	}
+}
+
+func TestGenerateSAIDNumberAtRejectsDatesOutsideTheWindow(t *testing.T) {
+	now := time.Date(2021, time.June, 1, 15, 0, 0, 0, time.UTC)
+	spec := SAIDSpec{Gender: Male, Citizenship: Citizen, Sequence: 17}
+
+	for _, dob := range []time.Time{
+		time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC), // would parse as 2000-01-01
+		time.Date(2030, time.May, 5, 0, 0, 0, 0, time.UTC),     // would parse as 1930-05-05
+		time.Date(2021, time.June, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(1921, time.June, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		spec.DateOfBirth = dob
+		_, err := GenerateSAIDNumberAt(spec, now)
+		assert.Error(t, err, dob)
+	}
+
+	// both ends of the window round trip
+	for _, dob := range []time.Time{
+		time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(1921, time.June, 2, 0, 0, 0, 0, time.UTC),
+	} {
+		spec.DateOfBirth = dob
+		id, err := GenerateSAIDNumberAt(spec, now)
+		require.NoError(t, err, dob)
+		details, err := ParseSAIDNumberAt(id, now)
+		require.NoError(t, err, id)
+		assert.Equal(t, dob, details.DateOfBirth, id)
+	}
+}
+
+func TestSAIDGeneratorIsReproducible(t *testing.T) {
+	now := time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+	a, b := NewSAIDGenerator(42, now), NewSAIDGenerator(42, now)
+	for i := 0; i < 100; i++ {
+		idA, _ := a.Next()
+		idB, _ := b.Next()
+		require.Equal(t, idA, idB)
+	}
+}
+
+func TestSAIDGeneratorRoundTrips(t *testing.T) {
+	now := time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+	gen := NewSAIDGenerator(1, now)
+	parser := NewSAIDParser(WithClock(func() time.Time { return now }))
+
+	for i := 0; i < 1000; i++ {
+		id, spec := gen.Next()
+		idDetails, err := parser.Parse(id)
+		require.NoError(t, err, id)
+		assert.Equal(t, spec.DateOfBirth, idDetails.DateOfBirth, id)
+		assert.Equal(t, spec.Gender, idDetails.Gender, id)
+		assert.Equal(t, spec.Citizenship, idDetails.Citizenship, id)
+	}
+}
@@ -0,0 +1,79 @@
+package said
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode"
+)
+
+// MaskSAIDNumber keeps the date of birth (the first 6 characters) and replaces every later
+// character with '*', leaving spaces and dashes in place so the layout still shows,
+// e.g. "910310*******" and "910310 **** * **". It doesn't validate id, so it is safe to use
+// on input that failed to parse.
+func MaskSAIDNumber(id string) string {
+	var b strings.Builder
+	b.Grow(len(id))
+	kept := 0
+	for _, r := range id {
+		switch {
+		case isSeparator(r):
+			b.WriteRune(r)
+		case kept < 6:
+			b.WriteRune(r)
+			kept++
+		default:
+			b.WriteByte('*')
+		}
+	}
+	return b.String()
+}
+
+// isSeparator matches what NormaliseSAIDNumber strips between digits
+func isSeparator(r rune) bool {
+	return unicode.IsSpace(r) || unicode.Is(unicode.Pd, r)
+}
+
+// PseudonymiseSAIDNumber returns the hex HMAC-SHA256 of id under key, the same id and key always give the
+// same pseudonym so records can still be joined, but it can't be reversed without the key. id is normalised
+// first, so "910310 5017 0 84" and "9103105017084" give the same pseudonym.
+func PseudonymiseSAIDNumber(id string, key []byte) string {
+	id, _ = NormaliseSAIDNumber(id)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// String masks the ID so that it never prints in full, use string(s) for the raw number
+func (s SAID) String() string {
+	return MaskSAIDNumber(string(s))
+}
+
+// GoString masks the ID in %#v output
+func (s SAID) GoString() string {
+	return fmt.Sprintf("said.SAID(%q)", MaskSAIDNumber(string(s)))
+}
+
+// LogValue masks the ID in slog output
+func (s SAID) LogValue() slog.Value {
+	return slog.StringValue(MaskSAIDNumber(string(s)))
+}
+
+// GoString prints the details for %#v without the raw segments, which would give the ID away
+func (d SAIDDetails) GoString() string {
+	return fmt.Sprintf("said.SAIDDetails{ID: %q, Gender: %q, DateOfBirth: %q, Age: %d, Citizenship: %q}",
+		MaskSAIDNumber(d.Number()), d.Gender, d.DateOfBirth.Format("2006-01-02"), d.Age, d.Citizenship.String())
+}
+
+// LogValue logs the details with the ID masked
+func (d SAIDDetails) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", MaskSAIDNumber(d.Number())),
+		slog.String("gender", string(d.Gender)),
+		slog.String("date_of_birth", d.DateOfBirth.Format("2006-01-02")),
+		slog.String("citizenship", d.Citizenship.String()),
+	)
+}
@@ -0,0 +1,84 @@
+package said
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskSAIDNumber(t *testing.T) {
+	tests := map[string]string{
+		"9103105017084":    "910310*******",
+		"910310 5017 0 84": "910310 **** * **",
+		" 910310-5017-084": " 910310-****-***",
+		"91 03 10 5017084": "91 03 10 *******",
+		"91031":            "91031",
+		"":                 "",
+		"９103105017084":    "９10310*******",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, MaskSAIDNumber(in), in)
+	}
+}
+
+func TestPseudonymiseSAIDNumber(t *testing.T) {
+	key := []byte("secret")
+	a := PseudonymiseSAIDNumber("9103105017084", key)
+	assert.Len(t, a, 64)
+	assert.Equal(t, a, PseudonymiseSAIDNumber("9103105017084", key))
+	assert.NotEqual(t, a, PseudonymiseSAIDNumber("9604185215084", key))
+	assert.NotEqual(t, a, PseudonymiseSAIDNumber("9103105017084", []byte("other")))
+	assert.NotContains(t, a, "9103105017084")
+
+	// the same ID however it is written
+	for _, id := range []string{"910310 5017 0 84", " 9103105017084", "910310-5017-084\n", "９103105017084"} {
+		assert.Equal(t, a, PseudonymiseSAIDNumber(id, key), id)
+	}
+}
+
+func TestSAIDNeverPrintsInFull(t *testing.T) {
+	id := SAID("9103105017084")
+	assert.Equal(t, "910310*******", fmt.Sprint(id))
+	assert.Equal(t, "id 910310*******", fmt.Sprintf("id %s", id))
+	assert.Equal(t, `said.SAID("910310*******")`, fmt.Sprintf("%#v", id))
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	logger.Info("parse failed", "id", id)
+
+	details, err := testParser.Parse("9103105017084")
+	require.NoError(t, err)
+	logger.Info("parsed", "details", *details)
+
+	out := buf.String()
+	assert.NotContains(t, out, "9103105017084")
+	assert.Contains(t, out, "id=910310*******")
+	assert.Contains(t, out, "details.id=910310******* details.gender=M details.date_of_birth=1991-03-10 details.citizenship=citizen")
+	assert.Equal(t, 2, strings.Count(out, "\n"))
+}
+
+func TestSAIDDetailsNeverPrintInFull(t *testing.T) {
+	details, err := testParser.Parse("9103105017084")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	logger := log.New(&buf, "", 0)
+	logger.Printf("%v", details)
+	logger.Printf("%s", *details)
+	logger.Printf("%+v", details)
+	logger.Printf("%#v", details)
+	logger.Println(details)
+
+	out := buf.String()
+	assert.Equal(t, 5, strings.Count(out, "\n"))
+	assert.NotContains(t, out, "9103105017084")
+	assert.NotContains(t, out, "5017")
+	assert.Contains(t, out, "910310*******\n910310*******\n910310*******\n")
+	assert.Contains(t, out, `said.SAIDDetails{ID: "910310*******", Gender: "M", DateOfBirth: "1991-03-10", Age: 30, Citizenship: "citizen"}`)
+}
@@ -0,0 +1,96 @@
+package said
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type EditKind int
+
+const (
+	EditTrimmed   EditKind = iota + 1 // leading or trailing whitespace removed
+	EditSeparator                     // space or dash between digits removed
+	EditDigit                         // non-ASCII decimal digit replaced with its ASCII equivalent
+)
+
+func (k EditKind) String() string {
+	switch k {
+	case EditTrimmed:
+		return "trimmed"
+	case EditSeparator:
+		return "separator"
+	case EditDigit:
+		return "digit"
+	}
+	return "unknown"
+}
+
+// Edit is one change NormaliseSAIDNumber made to its input
+type Edit struct {
+	Kind EditKind
+	Pos  int  // byte offset in the original input
+	Rune rune // the character that was removed or replaced
+}
+
+// WithNormalisation makes Parse accept pasted input such as " 910310 5017 0 84" or "910310-5017-084" by running
+// it through NormaliseSAIDNumber first, error positions refer to the normalised number. Without it Parse only
+// accepts exactly 13 ASCII digits. Use ParseNormalised to see what was changed.
+func WithNormalisation() SAIDParserOption {
+	return func(p *SAIDParser) {
+		p.normalise = true
+	}
+}
+
+// NormaliseSAIDNumber strips surrounding whitespace and any spaces or dashes between digits, and converts
+// other scripts' decimal digits (e.g. fullwidth or Arabic-Indic) to ASCII. Anything else is left in place for
+// Parse to reject. edits is nil when s was already normalised.
+func NormaliseSAIDNumber(s string) (normalised string, edits []Edit) {
+	if strings.Trim(s, "0123456789") == "" {
+		return s, nil
+	}
+
+	start := len(s) - len(strings.TrimLeftFunc(s, unicode.IsSpace))
+	end := len(strings.TrimRightFunc(s, unicode.IsSpace))
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case i < start || i >= end:
+			edits = append(edits, Edit{Kind: EditTrimmed, Pos: i, Rune: r})
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case isSeparator(r):
+			edits = append(edits, Edit{Kind: EditSeparator, Pos: i, Rune: r})
+		case unicode.IsDigit(r):
+			b.WriteByte('0' + digitValue(r))
+			edits = append(edits, Edit{Kind: EditDigit, Pos: i, Rune: r})
+		default:
+			b.WriteString(s[i : i+size]) // as is, including invalid UTF-8
+		}
+		i += size
+	}
+
+	if edits == nil {
+		return s, nil // only characters Parse will reject
+	}
+	return b.String(), edits
+}
+
+// digitValue returns the value of a Unicode decimal digit, Unicode allocates these
+// in contiguous runs of 0-9 so it is the offset into the run modulo 10
+func digitValue(r rune) byte {
+	for _, rng := range unicode.Nd.R16 {
+		if r >= rune(rng.Lo) && r <= rune(rng.Hi) {
+			return byte((r - rune(rng.Lo)) % 10)
+		}
+	}
+	for _, rng := range unicode.Nd.R32 {
+		if r >= rune(rng.Lo) && r <= rune(rng.Hi) {
+			return byte((r - rune(rng.Lo)) % 10)
+		}
+	}
+	return 0
+}
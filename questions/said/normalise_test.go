@@ -0,0 +1,78 @@
+package said
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormaliseSAIDNumber(t *testing.T) {
+	tests := []struct {
+		in    string
+		out   string
+		edits []Edit
+	}{
+		{"9103105017084", "9103105017084", nil},
+		{"910310 5017 0 84", "9103105017084", []Edit{
+			{EditSeparator, 6, ' '}, {EditSeparator, 11, ' '}, {EditSeparator, 13, ' '},
+		}},
+		{" 910310-5017-084\n", "9103105017084", []Edit{
+			{EditTrimmed, 0, ' '}, {EditSeparator, 7, '-'}, {EditSeparator, 12, '-'}, {EditTrimmed, 16, '\n'},
+		}},
+		{"910310 5017–0 84", "9103105017084", []Edit{ // no-break space and en dash
+			{EditSeparator, 6, ' '}, {EditSeparator, 12, '–'}, {EditSeparator, 16, ' '},
+		}},
+		{"９10310501708٤", "9103105017084", []Edit{ // fullwidth 9 and Arabic-Indic 4
+			{EditDigit, 0, '９'}, {EditDigit, 14, '٤'},
+		}},
+		{"91031050x7084", "91031050x7084", nil},
+		{"9103 1050x7084", "91031050x7084", []Edit{{EditSeparator, 4, ' '}}},
+	}
+	for _, tt := range tests {
+		out, edits := NormaliseSAIDNumber(tt.in)
+		assert.Equal(t, tt.out, out, tt.in)
+		assert.Equal(t, tt.edits, edits, tt.in)
+	}
+}
+
+func TestDigitValue(t *testing.T) {
+	for _, zero := range []rune{'0', '٠', '۰', '०', '০', '๐', '０', '𝟎', '𝟘'} {
+		for d := rune(0); d <= 9; d++ {
+			assert.Equal(t, byte(d), digitValue(zero+d), "%U", zero+d)
+		}
+	}
+}
+
+func TestParseWithNormalisation(t *testing.T) {
+	lenient := NewSAIDParser(WithClock(testParser.now), WithNormalisation())
+
+	idDetails, err := lenient.Parse(" 910310 5017 0 84 ")
+	require.NoError(t, err)
+	assert.Equal(t, "9103105017084", idDetails.Number())
+
+	_, err = lenient.Parse("910310_5017_0_84")
+	assert.ErrorIs(t, err, ErrLength)
+
+	// strict is the default
+	_, err = testParser.Parse("910310 5017 0 84")
+	assert.ErrorIs(t, err, ErrLength)
+}
+
+func TestParseNormalised(t *testing.T) {
+	// works on a strict parser too
+	idDetails, edits, err := testParser.ParseNormalised(" 910310 5017 0 84 ")
+	require.NoError(t, err)
+	assert.Equal(t, "9103105017084", idDetails.Number())
+	assert.Len(t, edits, 5)
+	assert.Equal(t, Edit{Kind: EditTrimmed, Pos: 0, Rune: ' '}, edits[0])
+
+	plain, edits, err := testParser.ParseNormalised("9103105017084")
+	require.NoError(t, err)
+	assert.Nil(t, edits)
+	assert.True(t, *idDetails == *plain, "SAIDDetails stays comparable")
+
+	_, edits, err = testParser.ParseNormalised("910310-5017-083")
+	assert.ErrorIs(t, err, ErrChecksum)
+	assert.Nil(t, edits)
+}
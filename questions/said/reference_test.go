@@ -0,0 +1,207 @@
+package said
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"greystonetec.co.za/tests/go-candidate-test/luhn"
+)
+
+// parseReference is Parse as it was before ParseInto, kept to check the fast path against
+func parseReference(p *SAIDParser, candidateString string) (*SAIDDetails, error) {
+	now := p.now()
+
+	if p.normalise {
+		candidateString, _ = NormaliseSAIDNumber(candidateString)
+	}
+
+	if len(candidateString) != 13 {
+		pos := len(candidateString) // first missing character
+		if pos > 13 {
+			pos = 13 // first extra character
+		}
+		return nil, &ValidationError{Err: ErrLength, Pos: pos}
+	}
+
+	// not strconv.Atoi, that allows a leading sign
+	for i := 0; i < len(candidateString); i++ {
+		if candidateString[i] < '0' || candidateString[i] > '9' {
+			return nil, &ValidationError{Err: ErrNonNumeric, Pos: i}
+		}
+	}
+
+	id := candidateString
+	var details = &SAIDDetails{
+		DOBDigits:        id[:6],
+		SequenceDigits:   id[6:10],
+		CitizenshipDigit: int(id[10] - '0'),
+		RaceDigit:        int(id[11] - '0'),
+		ChecksumDigit:    int(id[12] - '0'),
+	}
+
+	// 0000-4999 female, 5000-9999 male
+	gender, _ := strconv.Atoi(id[6:10])
+	if gender >= 5000 {
+		details.Gender = Male
+		details.Sequence = gender - 5000
+	} else {
+		details.Gender = Female
+		details.Sequence = gender
+	}
+
+	// Get date of birth
+	dob, err := resolveDateOfBirthReference(id[:6], now)
+	if err != nil {
+		return nil, err
+	}
+	details.DateOfBirth = dob
+	details.Age = ageAtReference(dob, now)
+
+	// check citizen
+	details.Citizenship = citizenshipFromDigit(details.CitizenshipDigit)
+	if details.Citizenship == CitizenshipInvalid && p.strictCitizenship {
+		return nil, &ValidationError{Err: ErrInvalidCitizenship, Pos: 10}
+	}
+	details.SACitizen = details.Citizenship == Citizen
+
+	if !luhn.Validate(id) {
+		return nil, &ValidationError{Err: ErrChecksum, Pos: 12}
+	}
+
+	return details, nil
+}
+
+// resolveDateOfBirthReference turns YYMMDD digits into a date, picking the century so that the
+// date falls within the 100 years up to and including reference
+func resolveDateOfBirthReference(yymmdd string, reference time.Time) (time.Time, error) {
+	yy, _ := strconv.Atoi(yymmdd[0:2])
+	mm, _ := strconv.Atoi(yymmdd[2:4])
+	dd, _ := strconv.Atoi(yymmdd[4:6])
+
+	year := reference.Year() - reference.Year()%100 + yy
+	if year > reference.Year() ||
+		(year == reference.Year() && (mm > int(reference.Month()) || (mm == int(reference.Month()) && dd > reference.Day()))) {
+		year -= 100
+	}
+
+	if mm < 1 || mm > 12 {
+		return time.Time{}, &ValidationError{Err: ErrInvalidDate, Pos: 2}
+	}
+
+	// time.Date normalises out of range values (Feb 30 -> Mar 2), so reject anything that moved
+	dob := time.Date(year, time.Month(mm), dd, 0, 0, 0, 0, time.UTC)
+	if dob.Year() != year || int(dob.Month()) != mm || dob.Day() != dd {
+		return time.Time{}, &ValidationError{Err: ErrInvalidDate, Pos: 4}
+	}
+	return dob, nil
+}
+
+// ageAtReference returns the age in whole years on the calendar date of now, a year is only
+// counted once the birthday has been reached (29 Feb birthdays count from 1 Mar)
+func ageAtReference(dob, now time.Time) int {
+	age := now.Year() - dob.Year()
+	if now.Month() < dob.Month() || (now.Month() == dob.Month() && now.Day() < dob.Day()) {
+		age--
+	}
+	return age
+}
+
+// parseCorpus is shared by the equivalence test and benchmarks: generated valid IDs, every
+// single digit mutation of some of them and the hand picked cases from the tests
+func parseCorpus() []string {
+	corpus := []string{
+		"9103105017084", "9604185215084", "9101015017087", "9101015017089", "910310501708",
+		"91031050170844", "", "91031050x7084", "+910310501708", "9102305017084", "9113105017083",
+		"9100105017080", "0102295017085", "0002295017087", "9103105017381", "9103105017183",
+		"9103105017282", "910310 5017 0 84",
+	}
+
+	gen := NewSAIDGenerator(14, time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC))
+	for i := 0; i < 1000; i++ {
+		id, _ := gen.Next()
+		corpus = append(corpus, id)
+		if i%50 == 0 {
+			for pos := 0; pos < len(id); pos++ {
+				b := []byte(id)
+				b[pos] = '0' + (b[pos]-'0'+5)%10
+				corpus = append(corpus, string(b))
+			}
+		}
+	}
+	return corpus
+}
+
+func TestParseMatchesReference(t *testing.T) {
+	for _, parser := range []*SAIDParser{
+		testParser,
+		NewSAIDParser(WithClock(testParser.now), WithStrictCitizenship(false)),
+		NewSAIDParser(WithClock(testParser.now), WithNormalisation()),
+	} {
+		for _, id := range parseCorpus() {
+			want, wantErr := parseReference(parser, id)
+			got, err := parser.Parse(id)
+			assert.Equal(t, wantErr, err, id)
+			assert.Equal(t, want, got, id)
+		}
+	}
+}
+
+func TestParseIntoDoesNotAllocate(t *testing.T) {
+	var details SAIDDetails
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := testParser.ParseInto("9103105017084", &details); err != nil {
+			t.Fatal(err)
+		}
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestParseIntoLeavesDetailsOnError(t *testing.T) {
+	details := SAIDDetails{Gender: Female}
+	require.Error(t, testParser.ParseInto("9103105017083", &details))
+	assert.Equal(t, SAIDDetails{Gender: Female}, details)
+}
+
+func BenchmarkParse(b *testing.B) {
+	corpus := parseCorpus()
+
+	b.Run("reference", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = parseReference(testParser, corpus[i%len(corpus)])
+		}
+	})
+	b.Run("Parse", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = testParser.Parse(corpus[i%len(corpus)])
+		}
+	})
+	b.Run("ParseInto", func(b *testing.B) {
+		b.ReportAllocs()
+		var details SAIDDetails
+		for i := 0; i < b.N; i++ {
+			_ = testParser.ParseInto(corpus[i%len(corpus)], &details)
+		}
+	})
+}
+
+func BenchmarkParseValid(b *testing.B) {
+	b.Run("reference", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = parseReference(testParser, "9103105017084")
+		}
+	})
+	b.Run("ParseInto", func(b *testing.B) {
+		b.ReportAllocs()
+		var details SAIDDetails
+		for i := 0; i < b.N; i++ {
+			_ = testParser.ParseInto("9103105017084", &details)
+		}
+	})
+}
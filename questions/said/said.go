@@ -0,0 +1,317 @@
+// Package said parses, validates and generates South African ID numbers (YYMMDDSSSSCAZ).
+package said
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"greystonetec.co.za/tests/go-candidate-test/luhn"
+)
+
+type (
+	Gender string
+)
+
+const (
+	Male   Gender = "M"
+	Female Gender = "F"
+)
+
+// CitizenshipStatus is the C digit, the zero value is CitizenshipInvalid
+type CitizenshipStatus int
+
+const (
+	CitizenshipInvalid CitizenshipStatus = iota // any digit other than 0-2
+	Citizen                                     // 0, born a SA citizen
+	PermanentResident                           // 1
+	Refugee                                     // 2
+)
+
+// citizenshipFromDigit maps the C digit to its status
+func citizenshipFromDigit(d int) CitizenshipStatus {
+	switch d {
+	case 0:
+		return Citizen
+	case 1:
+		return PermanentResident
+	case 2:
+		return Refugee
+	}
+	return CitizenshipInvalid
+}
+
+// Digit returns the C digit for the status, or -1 for CitizenshipInvalid
+func (c CitizenshipStatus) Digit() int {
+	switch c {
+	case Citizen:
+		return 0
+	case PermanentResident:
+		return 1
+	case Refugee:
+		return 2
+	}
+	return -1
+}
+
+func (c CitizenshipStatus) String() string {
+	switch c {
+	case Citizen:
+		return "citizen"
+	case PermanentResident:
+		return "permanent_resident"
+	case Refugee:
+		return "refugee"
+	}
+	return "invalid"
+}
+
+type SAIDDetails struct {
+	Gender      Gender
+	Age         int
+	SACitizen   bool // same as Citizenship == Citizen
+	Citizenship CitizenshipStatus
+	DateOfBirth time.Time
+
+	// raw segments of YYMMDDSSSSCAZ, e.g. for audit exports
+	DOBDigits        string // YYMMDD
+	SequenceDigits   string // SSSS, 0000-4999 female and 5000-9999 male
+	Sequence         int    // SSSS within the range of the gender, 0-4999
+	CitizenshipDigit int    // C
+	RaceDigit        int    // A, historically race, 8 (or 9) on current IDs
+	ChecksumDigit    int    // Z
+}
+
+// String masks the ID so that it never prints in full, see MaskSAIDNumber
+func (d SAIDDetails) String() string {
+	return MaskSAIDNumber(d.Number())
+}
+
+// Canonical returns the ID in the canonical spaced form, e.g. "910310 5017 0 84"
+func (d SAIDDetails) Canonical() string {
+	return fmt.Sprintf("%s %s %d %d%d", d.DOBDigits, d.SequenceDigits, d.CitizenshipDigit, d.RaceDigit, d.ChecksumDigit)
+}
+
+// Number returns the ID as 13 digits without separators
+func (d SAIDDetails) Number() string {
+	return fmt.Sprintf("%s%s%d%d%d", d.DOBDigits, d.SequenceDigits, d.CitizenshipDigit, d.RaceDigit, d.ChecksumDigit)
+}
+
+var (
+	ErrLength             = errors.New("invalid length")
+	ErrNonNumeric         = errors.New("non-numeric character")
+	ErrChecksum           = errors.New("luhn checksum failed")
+	ErrInvalidDate        = errors.New("invalid date of birth")
+	ErrInvalidCitizenship = errors.New("invalid citizenship digit")
+)
+
+// ValidationError is returned for every ID that fails to parse, Err is one of the
+// sentinel errors above (use errors.Is) and Pos is the 0 based position of the
+// offending character
+type ValidationError struct {
+	Err error
+	Pos int
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%v at position %d", e.Err, e.Pos)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCode returns a stable identifier for a parse error, e.g. for batch results and API responses
+func ErrorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrLength):
+		return "length"
+	case errors.Is(err, ErrNonNumeric):
+		return "non_numeric"
+	case errors.Is(err, ErrChecksum):
+		return "checksum"
+	case errors.Is(err, ErrInvalidDate):
+		return "invalid_date"
+	case errors.Is(err, ErrInvalidCitizenship):
+		return "invalid_citizenship"
+	}
+	return "invalid"
+}
+
+// SAIDParser parses SA ID numbers relative to a clock, which is used both to resolve
+// the century of the birth year and to calculate age
+type SAIDParser struct {
+	now               func() time.Time
+	strictCitizenship bool
+	normalise         bool
+}
+
+type SAIDParserOption func(*SAIDParser)
+
+// WithClock sets the source of "now", defaults to the wall clock
+func WithClock(now func() time.Time) SAIDParserOption {
+	return func(p *SAIDParser) {
+		p.now = now
+	}
+}
+
+// WithStrictCitizenship controls whether a C digit other than 0-2 is rejected with ErrInvalidCitizenship
+// (the default) or accepted as CitizenshipInvalid
+func WithStrictCitizenship(strict bool) SAIDParserOption {
+	return func(p *SAIDParser) {
+		p.strictCitizenship = strict
+	}
+}
+
+func NewSAIDParser(opts ...SAIDParserOption) *SAIDParser {
+	p := &SAIDParser{
+		now:               time.Now,
+		strictCitizenship: true,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// This is passed as a string because we are not performing mathematical operations against the actual input
+func ParseSAIDNumber(candidateString string) (*SAIDDetails, error) {
+	return NewSAIDParser().Parse(candidateString)
+}
+
+// ParseSAIDNumberAt parses the ID as if today were reference, i.e. the date of birth is taken
+// to be the latest date matching YYMMDD that is not after reference and age is calculated at reference
+func ParseSAIDNumberAt(candidateString string, reference time.Time) (*SAIDDetails, error) {
+	return NewSAIDParser(WithClock(func() time.Time { return reference })).Parse(candidateString)
+}
+
+// Parse returns the details of a valid ID, or a nil details and a *ValidationError
+func (p *SAIDParser) Parse(candidateString string) (*SAIDDetails, error) {
+	var details SAIDDetails
+	if err := p.ParseInto(candidateString, &details); err != nil {
+		return nil, err
+	}
+	return &details, nil
+}
+
+// ParseNormalised runs s through NormaliseSAIDNumber, whether or not the parser has WithNormalisation,
+// and parses the result. edits are the changes made to s, nil when it was already normal, error
+// positions refer to the normalised number.
+func (p *SAIDParser) ParseNormalised(s string) (details *SAIDDetails, edits []Edit, err error) {
+	s, edits = NormaliseSAIDNumber(s)
+	details, err = p.Parse(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	return details, edits, nil
+}
+
+// ParseInto is Parse without any heap allocation for a valid ID (unless normalising), for validating
+// large volumes. details is only written when the ID is valid.
+func (p *SAIDParser) ParseInto(candidateString string, details *SAIDDetails) error {
+	if p.normalise {
+		candidateString, _ = NormaliseSAIDNumber(candidateString)
+	}
+
+	if len(candidateString) != 13 {
+		pos := len(candidateString) // first missing character
+		if pos > 13 {
+			pos = 13 // first extra character
+		}
+		return &ValidationError{Err: ErrLength, Pos: pos}
+	}
+
+	// not strconv.Atoi, that allows a leading sign
+	for i := 0; i < len(candidateString); i++ {
+		if candidateString[i] < '0' || candidateString[i] > '9' {
+			return &ValidationError{Err: ErrNonNumeric, Pos: i}
+		}
+	}
+
+	id := candidateString
+	d := SAIDDetails{
+		DOBDigits:        id[:6],
+		SequenceDigits:   id[6:10],
+		CitizenshipDigit: int(id[10] - '0'),
+		RaceDigit:        int(id[11] - '0'),
+		ChecksumDigit:    int(id[12] - '0'),
+	}
+
+	// 0000-4999 female, 5000-9999 male
+	gender := digits(id, 6, 4)
+	if gender >= 5000 {
+		d.Gender = Male
+		d.Sequence = gender - 5000
+	} else {
+		d.Gender = Female
+		d.Sequence = gender
+	}
+
+	// Get date of birth
+	now := p.now()
+	dob, err := resolveDateOfBirth(id[:6], now)
+	if err != nil {
+		return err
+	}
+	d.DateOfBirth = dob
+	d.Age = ageAt(dob, now)
+
+	// check citizen
+	d.Citizenship = citizenshipFromDigit(d.CitizenshipDigit)
+	if d.Citizenship == CitizenshipInvalid && p.strictCitizenship {
+		return &ValidationError{Err: ErrInvalidCitizenship, Pos: 10}
+	}
+	d.SACitizen = d.Citizenship == Citizen
+
+	if !luhn.Validate(id) {
+		return &ValidationError{Err: ErrChecksum, Pos: 12}
+	}
+
+	*details = d
+	return nil
+}
+
+// digits returns the value of the n ASCII digits of s starting at i
+func digits(s string, i, n int) int {
+	v := 0
+	for _, c := range []byte(s[i : i+n]) {
+		v = v*10 + int(c-'0')
+	}
+	return v
+}
+
+// resolveDateOfBirth turns YYMMDD digits into a date, picking the century so that the
+// date falls within the 100 years up to and including reference
+func resolveDateOfBirth(yymmdd string, reference time.Time) (time.Time, error) {
+	yy, mm, dd := digits(yymmdd, 0, 2), digits(yymmdd, 2, 2), digits(yymmdd, 4, 2)
+	ry, rm, rd := reference.Date()
+
+	year := ry - ry%100 + yy
+	if year > ry || (year == ry && (mm > int(rm) || (mm == int(rm) && dd > rd))) {
+		year -= 100
+	}
+
+	if mm < 1 || mm > 12 {
+		return time.Time{}, &ValidationError{Err: ErrInvalidDate, Pos: 2}
+	}
+
+	// time.Date normalises out of range values (Feb 30 -> Mar 2), so reject anything that moved
+	dob := time.Date(year, time.Month(mm), dd, 0, 0, 0, 0, time.UTC)
+	if dob.Day() != dd {
+		return time.Time{}, &ValidationError{Err: ErrInvalidDate, Pos: 4}
+	}
+	return dob, nil
+}
+
+// ageAt returns the age in whole years on the calendar date of now, a year is only
+// counted once the birthday has been reached (29 Feb birthdays count from 1 Mar)
+func ageAt(dob, now time.Time) int {
+	ny, nm, nd := now.Date()
+	by, bm, bd := dob.Date()
+
+	age := ny - by
+	if nm < bm || (nm == bm && nd < bd) {
+		age--
+	}
+	return age
+}
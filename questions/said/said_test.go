@@ -0,0 +1,248 @@
+package said
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pinned so that ages don't drift with the wall clock
+var testParser = NewSAIDParser(WithClock(func() time.Time {
+	return time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+}))
+
+func TestInstructionQuestionPasses(t *testing.T) { // checks that question is valid
+	candidateString := "9103105017084"
+	idDetails, err := testParser.Parse(candidateString)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	require.NotNil(t, idDetails, "idDetails should not be nil")
+	assert.Equal(t, Male, idDetails.Gender)
+	assert.Equal(t, 30, idDetails.Age)
+	assert.True(t, idDetails.SACitizen)
+}
+
+func TestBrad2021Passes(t *testing.T) { // checks that question is valid
+	candidateString := "9604185215084"
+	idDetails, err := testParser.Parse(candidateString)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	require.NotNil(t, idDetails, "idDetails should not be nil")
+	assert.Equal(t, Male, idDetails.Gender)
+	assert.Equal(t, 25, idDetails.Age)
+	assert.True(t, idDetails.SACitizen)
+}
+
+func TestQuestion1PassesValidIDMaleSACitizen(t *testing.T) {
+	candidateString := "9101015017087"
+	idDetails, err := testParser.Parse(candidateString)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	require.NotNil(t, idDetails, "idDetails should not be nil")
+	assert.Equal(t, Male, idDetails.Gender)
+	assert.Equal(t, 30, idDetails.Age)
+	assert.True(t, idDetails.SACitizen)
+}
+
+func TestQuestion1ShouldFailInvalidIDLength(t *testing.T) {
+	candidateString := "910101501708"
+	_, err := ParseSAIDNumber(candidateString)
+	if err == nil {
+		t.Fatal("Should return error")
+	}
+}
+
+func TestQuestion1ShouldFailCheckSum(t *testing.T) {
+	candidateString := "9101015017089"
+	_, err := ParseSAIDNumber(candidateString)
+	if err == nil {
+		t.Fatal("Should return error")
+	}
+}
+
+func TestQuestion1ResolvesDateOfBirth(t *testing.T) {
+	reference := time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		id  string
+		dob time.Time
+	}{
+		{"9103105017084", time.Date(1991, time.March, 10, 0, 0, 0, 0, time.UTC)},
+		{"0001015017088", time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"2105315017082", time.Date(2021, time.May, 31, 0, 0, 0, 0, time.UTC)},
+		{"2106025017081", time.Date(1921, time.June, 2, 0, 0, 0, 0, time.UTC)}, // day after reference
+	}
+	for _, tt := range tests {
+		idDetails, err := ParseSAIDNumberAt(tt.id, reference)
+		require.NoError(t, err, tt.id)
+		assert.Equal(t, tt.dob, idDetails.DateOfBirth, tt.id)
+	}
+}
+
+func TestQuestion1ShouldFailInvalidDate(t *testing.T) {
+	reference := time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		id  string
+		pos int
+	}{
+		{"9102305017084", 4}, // 30 Feb
+		{"9113105017083", 2}, // month 13
+		{"9100105017080", 2}, // month 00
+		{"0102295017085", 4}, // 29 Feb on a non-leap year
+	}
+	for _, tt := range tests {
+		_, err := ParseSAIDNumberAt(tt.id, reference)
+		assert.ErrorIs(t, err, ErrInvalidDate, tt.id)
+		var validationErr *ValidationError
+		require.True(t, errors.As(err, &validationErr), tt.id)
+		assert.Equal(t, tt.pos, validationErr.Pos, tt.id)
+	}
+}
+
+func TestQuestion1ValidationErrors(t *testing.T) {
+	tests := []struct {
+		id  string
+		err error
+		pos int
+	}{
+		{"910310501708", ErrLength, 12},
+		{"91031050170844", ErrLength, 13},
+		{"", ErrLength, 0},
+		{"91031050x7084", ErrNonNumeric, 8},
+		{"+910310501708", ErrNonNumeric, 0},
+		{"9103105017083", ErrChecksum, 12},
+		{"9103105017381", ErrInvalidCitizenship, 10},
+	}
+	for _, tt := range tests {
+		idDetails, err := testParser.Parse(tt.id)
+		assert.Nil(t, idDetails, tt.id)
+		assert.ErrorIs(t, err, tt.err, tt.id)
+		var validationErr *ValidationError
+		require.True(t, errors.As(err, &validationErr), tt.id)
+		assert.Equal(t, tt.pos, validationErr.Pos, tt.id)
+	}
+}
+
+func TestQuestion1CountsExactBirthdays(t *testing.T) {
+	tests := []struct {
+		id  string
+		now time.Time
+		age int
+	}{
+		{"9103105017084", time.Date(2021, time.March, 9, 23, 59, 0, 0, time.UTC), 29},
+		{"9103105017084", time.Date(2021, time.March, 10, 0, 0, 0, 0, time.UTC), 30},
+		{"9103105017084", time.Date(2022, time.March, 9, 0, 0, 0, 0, time.UTC), 30},
+		{"0002295017087", time.Date(2021, time.February, 28, 0, 0, 0, 0, time.UTC), 20},
+		{"0002295017087", time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC), 21},
+	}
+	for _, tt := range tests {
+		now := tt.now
+		parser := NewSAIDParser(WithClock(func() time.Time { return now }))
+		idDetails, err := parser.Parse(tt.id)
+		require.NoError(t, err, tt.id)
+		assert.Equal(t, tt.age, idDetails.Age, "%s at %s", tt.id, tt.now)
+	}
+}
+
+func TestQuestion1GenderBoundary(t *testing.T) {
+	dob := time.Date(1991, time.March, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		gender   Gender
+		sequence int
+		ssss     string
+	}{
+		{Female, 0, "0000"},
+		{Female, 4999, "4999"},
+		{Male, 0, "5000"},
+		{Male, 4999, "9999"},
+	}
+	for _, tt := range tests {
+		id, err := GenerateSAIDNumber(SAIDSpec{DateOfBirth: dob, Gender: tt.gender, Citizenship: Citizen, Sequence: tt.sequence})
+		require.NoError(t, err)
+		require.Equal(t, tt.ssss, id[6:10])
+
+		idDetails, err := testParser.Parse(id)
+		require.NoError(t, err, id)
+		assert.Equal(t, tt.gender, idDetails.Gender, id)
+	}
+}
+
+func TestQuestion1Citizenship(t *testing.T) {
+	tests := []struct {
+		id          string
+		citizenship CitizenshipStatus
+		saCitizen   bool
+	}{
+		{"9103105017084", Citizen, true},
+		{"9103105017183", PermanentResident, false},
+		{"9103105017282", Refugee, false},
+	}
+	for _, tt := range tests {
+		idDetails, err := testParser.Parse(tt.id)
+		require.NoError(t, err, tt.id)
+		assert.Equal(t, tt.citizenship, idDetails.Citizenship, tt.id)
+		assert.Equal(t, tt.saCitizen, idDetails.SACitizen, tt.id)
+	}
+}
+
+func TestQuestion1StrictCitizenship(t *testing.T) {
+	const id = "9103105017381" // C digit 3
+
+	_, err := testParser.Parse(id)
+	assert.ErrorIs(t, err, ErrInvalidCitizenship)
+
+	lenient := NewSAIDParser(WithStrictCitizenship(false))
+	idDetails, err := lenient.Parse(id)
+	require.NoError(t, err)
+	assert.Equal(t, CitizenshipInvalid, idDetails.Citizenship)
+	assert.False(t, idDetails.SACitizen)
+}
+
+func TestCitizenshipStatusDigits(t *testing.T) {
+	for d := 0; d <= 9; d++ {
+		c := citizenshipFromDigit(d)
+		if d <= 2 {
+			assert.Equal(t, d, c.Digit())
+		} else {
+			assert.Equal(t, CitizenshipInvalid, c)
+			assert.Equal(t, -1, c.Digit())
+		}
+	}
+	assert.Equal(t, "permanent_resident", PermanentResident.String())
+	assert.Equal(t, "invalid", CitizenshipStatus(0).String())
+}
+
+func TestQuestion1RawSegments(t *testing.T) {
+	idDetails, err := testParser.Parse("9103105017084")
+	require.NoError(t, err)
+
+	assert.Equal(t, "910310", idDetails.DOBDigits)
+	assert.Equal(t, "5017", idDetails.SequenceDigits)
+	assert.Equal(t, 17, idDetails.Sequence)
+	assert.Equal(t, 0, idDetails.CitizenshipDigit)
+	assert.Equal(t, 8, idDetails.RaceDigit)
+	assert.Equal(t, 4, idDetails.ChecksumDigit)
+	assert.Equal(t, "910310 5017 0 84", idDetails.Canonical())
+	assert.Equal(t, "910310*******", idDetails.String())
+	assert.Equal(t, "9103105017084", idDetails.Number())
+}
+
+func TestSAIDDetailsNumberRoundTrips(t *testing.T) {
+	now := time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+	gen := NewSAIDGenerator(3, now)
+	for i := 0; i < 100; i++ {
+		id, spec := gen.Next()
+		idDetails, err := testParser.Parse(id)
+		require.NoError(t, err, id)
+		assert.Equal(t, id, idDetails.Number())
+		assert.Equal(t, spec.Sequence, idDetails.Sequence, id)
+	}
+}
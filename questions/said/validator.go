@@ -0,0 +1,90 @@
+package said
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidationTag is the go-playground/validator tag registered by RegisterValidation
+const ValidationTag = "said"
+
+// RegisterValidation registers the "said" tag on v for string fields (including SAID):
+//
+//	`validate:"said"`               a valid ID number
+//	`validate:"said=citizen"`       of a SA citizen
+//	`validate:"said=resident"`      of a SA citizen or permanent resident
+//	`validate:"said=adult"`         of someone aged 18 or older
+//	`validate:"said=citizen adult"` requirements can be combined with spaces
+//
+// opts configure the parser, e.g. WithClock for the adult check
+func RegisterValidation(v *validator.Validate, opts ...SAIDParserOption) error {
+	parser := NewSAIDParser(opts...)
+	return v.RegisterValidation(ValidationTag, func(fl validator.FieldLevel) bool {
+		_, ok := checkRequirements(parser, fl.Field().String(), fl.Param())
+		return ok
+	})
+}
+
+// RegisterTranslations registers English messages for the "said" tag, naming the first requirement the
+// value fails. opts should be the same as those given to RegisterValidation.
+func RegisterTranslations(v *validator.Validate, trans ut.Translator, opts ...SAIDParserOption) error {
+	messages := map[string]string{
+		ValidationTag:               "{0} must be a valid South African ID number",
+		ValidationTag + "-citizen":  "{0} must be the ID number of a South African citizen",
+		ValidationTag + "-resident": "{0} must be the ID number of a South African citizen or permanent resident",
+		ValidationTag + "-adult":    "{0} must be the ID number of someone aged 18 or older",
+	}
+	parser := NewSAIDParser(opts...)
+
+	return v.RegisterTranslation(ValidationTag, trans,
+		func(trans ut.Translator) error {
+			for key, msg := range messages {
+				if err := trans.Add(key, msg, false); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		func(trans ut.Translator, fe validator.FieldError) string {
+			key := ValidationTag
+			// not fmt.Sprint, SAID masks itself
+			if failed, _ := checkRequirements(parser, reflect.ValueOf(fe.Value()).String(), fe.Param()); failed != "" {
+				key += "-" + failed
+			}
+			msg, err := trans.T(key, fe.Field())
+			if err != nil {
+				return fe.(error).Error()
+			}
+			return msg
+		})
+}
+
+// checkRequirements returns whether id is valid and meets every requirement in params,
+// and if not the requirement it failed ("" for an invalid ID)
+func checkRequirements(parser *SAIDParser, id, params string) (failed string, ok bool) {
+	details, err := parser.Parse(id)
+	if err != nil {
+		return "", false
+	}
+
+	for _, param := range strings.Fields(params) {
+		switch param {
+		case "citizen":
+			ok = details.Citizenship == Citizen
+		case "resident":
+			ok = details.Citizenship == Citizen || details.Citizenship == PermanentResident
+		case "adult":
+			ok = details.Age >= 18
+		default:
+			panic(fmt.Sprintf("said: unknown validation parameter %q", param))
+		}
+		if !ok {
+			return param, false
+		}
+	}
+	return "", true
+}
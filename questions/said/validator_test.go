@@ -0,0 +1,90 @@
+package said
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type account struct {
+	ID      string `validate:"said"`
+	Owner   SAID   `validate:"omitempty,said=citizen adult"`
+	Manager string `validate:"omitempty,said=resident"`
+}
+
+func newTestValidator(t *testing.T) (*validator.Validate, ut.Translator) {
+	clock := WithClock(func() time.Time {
+		return time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+	})
+	v := validator.New()
+	require.NoError(t, RegisterValidation(v, clock))
+
+	english := en.New()
+	trans, _ := ut.New(english, english).GetTranslator("en")
+	require.NoError(t, RegisterTranslations(v, trans, clock))
+	return v, trans
+}
+
+func TestValidation(t *testing.T) {
+	v, _ := newTestValidator(t)
+
+	tests := []struct {
+		acc     account
+		invalid []string
+	}{
+		{account{ID: "9103105017084"}, nil},
+		{account{ID: "9103105017084", Owner: "9604185215084", Manager: "9103105017183"}, nil},
+		{account{ID: "9103105017083"}, []string{"ID"}},
+		{account{ID: "9103105017084", Owner: "9103105017183"}, []string{"Owner"}},     // permanent resident
+		{account{ID: "9103105017084", Owner: "0501015017087"}, []string{"Owner"}},     // 16 years old
+		{account{ID: "9103105017084", Manager: "9103105017282"}, []string{"Manager"}}, // refugee
+	}
+	for _, tt := range tests {
+		err := v.Struct(tt.acc)
+		if tt.invalid == nil {
+			assert.NoError(t, err, "%+v", tt.acc)
+			continue
+		}
+
+		var errs validator.ValidationErrors
+		require.True(t, errors.As(err, &errs), "%+v", tt.acc)
+		var fields []string
+		for _, fe := range errs {
+			fields = append(fields, fe.Field())
+		}
+		assert.Equal(t, tt.invalid, fields, "%+v", tt.acc)
+	}
+}
+
+func TestValidationTranslations(t *testing.T) {
+	v, trans := newTestValidator(t)
+
+	tests := []struct {
+		acc account
+		msg string
+	}{
+		{account{ID: "nope"}, "ID must be a valid South African ID number"},
+		{account{ID: "9103105017084", Owner: "9103105017183"}, "Owner must be the ID number of a South African citizen"},
+		{account{ID: "9103105017084", Owner: "0501015017087"}, "Owner must be the ID number of someone aged 18 or older"},
+		{account{ID: "9103105017084", Manager: "9103105017282"}, "Manager must be the ID number of a South African citizen or permanent resident"},
+	}
+	for _, tt := range tests {
+		var errs validator.ValidationErrors
+		require.True(t, errors.As(v.Struct(tt.acc), &errs))
+		require.Len(t, errs, 1)
+		assert.Equal(t, tt.msg, errs[0].Translate(trans))
+	}
+}
+
+func TestValidationUnknownParam(t *testing.T) {
+	v, _ := newTestValidator(t)
+	assert.Panics(t, func() {
+		_ = v.Var("9103105017084", "said=alien")
+	})
+}